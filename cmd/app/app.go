@@ -1,57 +1,86 @@
 package app
 
 import (
+	"context"
+	"fmt"
 	"github.com/gofiber/fiber/v2"
-	"github.com/spf13/viper"
 	"gorm.io/gorm"
+	"sync"
+	"sync/atomic"
 	"webTemplate/internal/adapters/config"
+	"webTemplate/internal/adapters/controller/api/errorhandler"
 	"webTemplate/internal/adapters/controller/api/validator"
+	"webTemplate/internal/adapters/database/storage"
 	"webTemplate/internal/adapters/logger"
+	"webTemplate/internal/domain/utils/password"
+	"webTemplate/internal/domain/utils/username"
 )
 
 // App is a struct that contains the fiber app, database connection, listen port, validator, logging boolean etc.
 type App struct {
+	Config    *config.Config
 	Fiber     *fiber.App
 	DB        *gorm.DB
+	Storages  *storage.Storages
 	Maileroo  config.MailerooConfig
 	Validator *validator.Validator
+
+	PasswordPolicy *password.Policy
+	PasswordHasher *password.Hasher
+	UsernameRules  *username.Rules
+
+	// stopWorkers cancels the workers started by StartWorkers, workers tracks them until they return
+	stopWorkers context.CancelFunc
+	workers     sync.WaitGroup
+	// shuttingDown is set when Shutdown starts, readiness checks fail from then on
+	shuttingDown atomic.Bool
 }
 
 // New is a function that creates a new app struct
-func New(config *config.Config) *App {
+func New(config *config.Config, storages *storage.Storages) *App {
 	fiberApp := fiber.New(fiber.Config{
 		// Global custom error handler
-		ErrorHandler: func(c *fiber.Ctx, err error) error {
-			return c.Status(fiber.StatusBadRequest).JSON(validator.GlobalErrorHandlerResp{
-				Success: false,
-				Message: err.Error(),
-			})
-		},
+		ErrorHandler: errorhandler.New(config.Settings.Debug),
 	},
 	)
 
 	return &App{
+		Config:    config,
 		Fiber:     fiberApp,
 		DB:        config.Database,
-		Maileroo:  config.Maileroo,
-		Validator: validator.New(),
+		Storages:  storages,
+		Maileroo:  config.Service.Maileroo,
+		Validator: validator.New(config.PasswordPolicy, config.UsernameRules),
+
+		PasswordPolicy: config.PasswordPolicy,
+		PasswordHasher: config.PasswordHasher,
+		UsernameRules:  config.UsernameRules,
 	}
 }
 
-// Start is a function that starts the app
-func (a *App) Start() {
-	if viper.GetBool("settings.listen-tls") {
+// ShuttingDown is a method that reports whether a graceful shutdown has started.
+func (a *App) ShuttingDown() bool {
+	return a.shuttingDown.Load()
+}
+
+// Start is a function that starts the http server, it blocks until the server is shut down
+func (a *App) Start() error {
+	addr := fmt.Sprintf(":%d", a.Config.Service.Backend.Port)
+
+	if a.Config.Settings.ListenTLS {
 		if err := a.Fiber.ListenTLS(
-			":"+viper.GetString("service.backend.port"),
-			viper.GetString("service.backend.certificate.cert-file"),
-			viper.GetString("service.backend.certificate.key-file"),
+			addr,
+			a.Config.Service.Backend.Certificate.CertFile,
+			a.Config.Service.Backend.Certificate.KeyFile,
 		); err != nil {
-			logger.Log.Panicf("failed to start listen (with tls): %v", err)
-		}
-	} else {
-		logger.Log.Debugf("port: %s", viper.GetString("service.backend.port"))
-		if err := a.Fiber.Listen(":" + viper.GetString("service.backend.port")); err != nil {
-			logger.Log.Panicf("failed to start listen (no tls): %v", err)
+			return fmt.Errorf("failed to start listen (with tls): %w", err)
 		}
+		return nil
+	}
+
+	logger.Log.Debugf("port: %d", a.Config.Service.Backend.Port)
+	if err := a.Fiber.Listen(addr); err != nil {
+		return fmt.Errorf("failed to start listen (no tls): %w", err)
 	}
+	return nil
 }
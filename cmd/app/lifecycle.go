@@ -0,0 +1,100 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+	"webTemplate/internal/adapters/logger"
+)
+
+// Run is a method that starts the http server and the background workers, then blocks until SIGINT or SIGTERM
+// and shuts the app down, see Shutdown. A second signal stops the app immediately.
+func (a *App) Run() error {
+	signalCtx, stopSignals := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stopSignals()
+
+	a.StartWorkers(context.Background())
+
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- a.Start()
+	}()
+
+	var errServe error
+	select {
+	case <-signalCtx.Done():
+		logger.Log.Info("Shutdown signal received")
+	case errServe = <-serverErr:
+		logger.Log.Errorf("Server stopped: %v", errServe)
+	}
+	// Restore the default behavior, so the next signal kills the process
+	stopSignals()
+
+	return errors.Join(errServe, a.Shutdown(a.Config.Service.Backend.ShutdownTimeout))
+}
+
+// Shutdown is a method that stops the app in order: reports not ready for the shutdown delay,
+// stops accepting connections and drains in-flight requests,
+// stops the workers, closes the database and flushes the logger.
+// Steps not finished before the timeout are cut short, the remaining ones still run.
+/*
+ * timeout time.Duration - the deadline of the whole shutdown, see service.backend.shutdown-timeout
+ */
+func (a *App) Shutdown(timeout time.Duration) error {
+	logger.Log.Infof("Shutting down, timeout %s...", timeout)
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	a.shuttingDown.Store(true)
+	if delay := a.Config.Service.Health.ShutdownDelay; delay > 0 {
+		logger.Log.Infof("Reporting not ready for %s...", delay)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+		}
+	}
+
+	var errs []error
+
+	logger.Log.Info("Draining http requests...")
+	if err := a.Fiber.ShutdownWithContext(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to shut down http server: %w", err))
+	}
+
+	logger.Log.Info("Stopping workers...")
+	if err := a.StopWorkers(ctx); err != nil {
+		errs = append(errs, err)
+	}
+
+	logger.Log.Info("Closing database...")
+	if a.DB != nil {
+		if sqlDB, err := a.DB.DB(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to get database pool: %w", err))
+		} else if err = sqlDB.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close database: %w", err))
+		}
+	}
+	for _, replica := range a.Config.Replicas {
+		if err := replica.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to close database replica: %w", err))
+		}
+	}
+	if err := a.Storages.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to close storages: %w", err))
+	}
+
+	err := errors.Join(errs...)
+	if err != nil {
+		logger.Log.Errorf("Shutdown finished with errors: %v", err)
+	} else {
+		logger.Log.Info("Shutdown finished")
+	}
+
+	// Syncing fails for terminals on some systems, there is nothing to flush then
+	_ = logger.Sync()
+	return err
+}
@@ -0,0 +1,57 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"time"
+	"webTemplate/internal/domain/service"
+)
+
+// StartWorkers is a function that starts the background workers of the app, they run until StopWorkers is called
+func (a *App) StartWorkers(ctx context.Context) {
+	ctx, a.stopWorkers = context.WithCancel(ctx)
+
+	outboxConfig := a.Config.Service.EmailOutbox
+
+	outboxWorker := service.NewOutboxWorker(
+		a.Storages.Outbox,
+		service.NewEmailService(a.Maileroo, a.Storages.Users),
+		service.OutboxWorkerConfig{
+			PollInterval: outboxConfig.PollInterval,
+			BatchSize:    outboxConfig.BatchSize,
+			MaxAttempts:  outboxConfig.MaxAttempts,
+			Lease:        time.Minute + outboxConfig.PollInterval,
+		},
+	)
+	a.runWorker(ctx, outboxWorker.Run)
+}
+
+// runWorker is a method that runs a worker in a goroutine tracked by StopWorkers.
+func (a *App) runWorker(ctx context.Context, run func(ctx context.Context)) {
+	a.workers.Add(1)
+	go func() {
+		defer a.workers.Done()
+		run(ctx)
+	}()
+}
+
+// StopWorkers is a method that cancels the workers and waits until they return or the context is done.
+func (a *App) StopWorkers(ctx context.Context) error {
+	if a.stopWorkers == nil {
+		return nil
+	}
+	a.stopWorkers()
+
+	stopped := make(chan struct{})
+	go func() {
+		a.workers.Wait()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("workers did not stop in time: %w", ctx.Err())
+	}
+}
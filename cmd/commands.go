@@ -0,0 +1,88 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"webTemplate/internal/domain/common/errorz"
+)
+
+// command is a struct that contains a subcommand of the binary.
+type command struct {
+	name        string
+	usage       string
+	description string
+	run         func(configPath string, args []string) error
+}
+
+// commands is a list of all subcommands, it is filled in init because usage refers to it.
+var commands []command
+
+func init() {
+	commands = []command{
+		{"serve", "serve [--migrate=false]", "Start the http server and the workers (default)", serve},
+		{"migrate", "migrate up|down [N]|status", "Migrate the database schema", migrate},
+		{"user", "user create-admin --email E --username U [--password P]", "Manage users", user},
+		{"seed", "seed [--users N] [--password P] [--force]", "Create demo users", seed},
+		{"config", "config check", "Check the config and print its problems", configCommand},
+		{"routes", "routes", "List the http routes", routes},
+	}
+}
+
+// run is a function that runs the subcommand named by the first argument, serve if there are no arguments.
+/*
+ * configPath string - the config file path, shared by all subcommands
+ * args []string - the subcommand name followed by its arguments
+ */
+func run(configPath string, args []string) error {
+	if len(args) == 0 {
+		args = []string{"serve"}
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			return describe(cmd.run(configPath, args[1:]))
+		}
+	}
+
+	usage()
+	return fmt.Errorf("unknown command %q", args[0])
+}
+
+// usage is a function that prints the global flags and the subcommands.
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(out, "Usage: %s [--config PATH] <command> [arguments]\n\nFlags:\n", os.Args[0])
+	flag.PrintDefaults()
+	fmt.Fprintln(out, "\nCommands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(out, "  %-58s %s\n", cmd.usage, cmd.description)
+	}
+}
+
+// describe is a function that adds the field errors of a validation error to its message, so they reach the terminal.
+func describe(err error) error {
+	var domainErr *errorz.Error
+	if !errors.As(err, &domainErr) || len(domainErr.Fields) == 0 {
+		return err
+	}
+
+	messages := make([]string, 0, len(domainErr.Fields))
+	for _, field := range domainErr.Fields {
+		messages = append(messages, field.Message)
+	}
+	return fmt.Errorf("%w: %s", err, strings.Join(messages, "; "))
+}
+
+// parseFlags is a function that parses the flags of a subcommand and rejects extra arguments.
+func parseFlags(flags *flag.FlagSet, args []string) error {
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments: %s", strings.Join(flags.Args(), " "))
+	}
+	return nil
+}
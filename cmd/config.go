@@ -0,0 +1,60 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"text/tabwriter"
+	"webTemplate/cmd/app"
+	"webTemplate/internal/adapters/config"
+	"webTemplate/internal/adapters/controller/api/setup"
+	"webTemplate/internal/adapters/database/storage"
+)
+
+// configCommand is a function that runs the "config check" subcommand.
+func configCommand(configPath string, args []string) error {
+	if len(args) != 1 || args[0] != "check" {
+		return errors.New("usage: config check")
+	}
+
+	if _, err := config.Load(configPath); err != nil {
+		return err
+	}
+	fmt.Printf("Config %s is valid\n", configPath)
+	return nil
+}
+
+// routes is a function that prints the method and path of every http route, without connecting to the database.
+func routes(configPath string, args []string) error {
+	if len(args) > 0 {
+		return errors.New("usage: routes")
+	}
+
+	appConfig, err := config.Load(configPath)
+	if err != nil {
+		return err
+	}
+	// The route table is written to stdout, so the logs mustn't be mixed into it when it's piped
+	appConfig.Settings.Logging.Outputs = []string{"stderr"}
+	if err = appConfig.InitLogger(); err != nil {
+		return err
+	}
+	mainApp := app.New(appConfig, storage.NewMemory())
+	setup.Setup(mainApp)
+
+	appRoutes := mainApp.Fiber.GetRoutes(true)
+	sort.SliceStable(appRoutes, func(i, j int) bool {
+		return appRoutes[i].Path < appRoutes[j].Path
+	})
+
+	out := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, route := range appRoutes {
+		// Fiber registers HEAD for every GET route
+		if route.Method == "HEAD" {
+			continue
+		}
+		fmt.Fprintf(out, "%s\t%s\n", route.Method, route.Path)
+	}
+	return out.Flush()
+}
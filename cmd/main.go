@@ -1,9 +1,10 @@
 package main
 
 import (
-	"webTemplate/cmd/app"
+	"flag"
+	"fmt"
+	"os"
 	"webTemplate/internal/adapters/config"
-	"webTemplate/internal/adapters/controller/api/setup"
 )
 
 // @title           WebTemplate API
@@ -20,9 +21,12 @@ import (
 // @name Authorization
 // @description "Type 'Bearer TOKEN' to correctly set the API Key"
 func main() {
-	appConfig := config.Configure()
-	mainApp := app.New(appConfig)
+	configPath := flag.String("config", "", "path to the config file, $APP_CONFIG or "+config.DefaultPath+" by default")
+	flag.Usage = usage
+	flag.Parse()
 
-	setup.Setup(mainApp)
-	mainApp.Start()
+	if err := run(config.Path(*configPath), flag.Args()); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
 }
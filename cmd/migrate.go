@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+	"webTemplate/internal/adapters/database/postgres"
+)
+
+// migrate is a function that runs the "migrate up|down [N]|status" subcommands.
+func migrate(configPath string, args []string) error {
+	if len(args) == 0 || len(args) > 2 {
+		return errors.New("usage: migrate up|down [N]|status")
+	}
+
+	steps := 1
+	if len(args) == 2 {
+		if args[0] != "down" {
+			return errors.New("usage: migrate up|down [N]|status")
+		}
+		var err error
+		if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+			return fmt.Errorf("invalid number of migrations %q", args[1])
+		}
+	}
+
+	appConfig, err := connect(configPath)
+	if err != nil {
+		return err
+	}
+	if appConfig.Database == nil {
+		return fmt.Errorf("migrations only apply to postgres, service.database.storage is %s", appConfig.Service.Database.Storage)
+	}
+	defer closeDatabase(appConfig.Database)
+	ctx := context.Background()
+
+	switch args[0] {
+	case "up":
+		return postgres.Migrate(ctx, appConfig.Database)
+	case "down":
+		return postgres.MigrateDown(ctx, appConfig.Database, steps)
+	case "status":
+		statuses, err := postgres.MigrationStatuses(ctx, appConfig.Database)
+		if err != nil {
+			return err
+		}
+		return printMigrationStatuses(statuses)
+	default:
+		return fmt.Errorf("unknown migrate command %q, use up, down or status", args[0])
+	}
+}
+
+// printMigrationStatuses is a function that prints a table of migrations and when they were applied.
+func printMigrationStatuses(statuses []postgres.MigrationStatus) error {
+	out := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(out, "VERSION\tNAME\tSTATUS")
+	for _, status := range statuses {
+		state := "pending"
+		switch {
+		case status.AppliedAt != nil && status.Up == "":
+			state = "applied, no file"
+		case status.Changed:
+			state = "applied, file changed"
+		case status.AppliedAt != nil:
+			state = "applied " + status.AppliedAt.Local().Format(time.DateTime)
+		}
+		fmt.Fprintf(out, "%d\t%s\t%s\n", status.Version, status.Name, state)
+	}
+	return out.Flush()
+}
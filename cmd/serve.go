@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"gorm.io/gorm"
+	"webTemplate/cmd/app"
+	"webTemplate/internal/adapters/config"
+	"webTemplate/internal/adapters/controller/api/setup"
+	"webTemplate/internal/adapters/database/postgres"
+	"webTemplate/internal/adapters/database/storage"
+)
+
+// serve is a function that starts the app and blocks until it is shut down.
+func serve(configPath string, args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ContinueOnError)
+	runMigrations := flags.Bool("migrate", true, "migrate the database before starting")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	appConfig, err := connect(configPath)
+	if err != nil {
+		return err
+	}
+	if appConfig.Database != nil {
+		if *runMigrations {
+			if err = postgres.Migrate(context.Background(), appConfig.Database); err != nil {
+				return err
+			}
+		}
+		if err = appConfig.ConnectReplicas(); err != nil {
+			return err
+		}
+	}
+	storages, err := storage.New(appConfig.Service.Database, appConfig.Database)
+	if err != nil {
+		return err
+	}
+	appConfig.Watch(configPath)
+
+	mainApp := app.New(appConfig, storages)
+	setup.Setup(mainApp)
+	return mainApp.Run()
+}
+
+// connect is a function that configures the app and connects to the database,
+// the database stays nil if service.database.storage doesn't use postgres.
+func connect(configPath string) (*config.Config, error) {
+	appConfig, err := config.Configure(configPath)
+	if err != nil {
+		return nil, err
+	}
+	if !storage.UsesPostgres(appConfig.Service.Database) {
+		return appConfig, nil
+	}
+	if err = appConfig.ConnectDatabase(); err != nil {
+		return nil, err
+	}
+	return appConfig, nil
+}
+
+// closeDatabase is a function that closes the connection pool of a command that doesn't run the app.
+func closeDatabase(db *gorm.DB) {
+	if db == nil {
+		return
+	}
+	if sqlDB, err := db.DB(); err == nil {
+		_ = sqlDB.Close()
+	}
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/mail"
+	"os"
+	"strings"
+	"webTemplate/internal/adapters/config"
+	"webTemplate/internal/adapters/database/storage"
+	"webTemplate/internal/domain/common/errorz"
+	"webTemplate/internal/domain/entity"
+	"webTemplate/internal/domain/service"
+)
+
+// defaultSeedPassword is the password of demo users, it meets the default password policy.
+const defaultSeedPassword = "Sunny-Meadow-Lantern-42"
+
+// user is a function that runs the "user" subcommands.
+func user(configPath string, args []string) error {
+	if len(args) == 0 || args[0] != "create-admin" {
+		return errors.New("usage: user create-admin --email E --username U [--password P]")
+	}
+
+	flags := flag.NewFlagSet("user create-admin", flag.ContinueOnError)
+	email := flags.String("email", "", "email of the admin")
+	name := flags.String("username", "", "username of the admin")
+	password := flags.String("password", "", "password of the admin, read from stdin if empty")
+	if err := parseFlags(flags, args[1:]); err != nil {
+		return err
+	}
+	if *email == "" || *name == "" {
+		return errors.New("--email and --username are required")
+	}
+	if *password == "" {
+		fmt.Fprint(os.Stderr, "Password: ")
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return fmt.Errorf("failed to read password: %w", err)
+		}
+		*password = strings.TrimRight(line, "\r\n")
+	}
+
+	appConfig, err := connect(configPath)
+	if err != nil {
+		return err
+	}
+	defer closeDatabase(appConfig.Database)
+
+	storages, err := storage.New(appConfig.Service.Database, appConfig.Database)
+	if err != nil {
+		return err
+	}
+	defer storages.Close()
+
+	admin, err := createUser(context.Background(), appConfig, storages.Users, *email, *name, *password, entity.RoleAdmin)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Admin %s (%s) created\n", admin.Username, admin.ID)
+	return nil
+}
+
+// seed is a function that creates verified demo users, existing ones are skipped.
+func seed(configPath string, args []string) error {
+	flags := flag.NewFlagSet("seed", flag.ContinueOnError)
+	count := flags.Int("users", 10, "number of demo users")
+	password := flags.String("password", defaultSeedPassword, "password of the demo users")
+	force := flags.Bool("force", false, "seed even if settings.debug is false")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	appConfig, err := connect(configPath)
+	if err != nil {
+		return err
+	}
+	defer closeDatabase(appConfig.Database)
+
+	if !appConfig.Settings.Debug && !*force {
+		return errors.New("demo users have a known password, seeding outside debug mode needs --force")
+	}
+
+	storages, err := storage.New(appConfig.Service.Database, appConfig.Database)
+	if err != nil {
+		return err
+	}
+	defer storages.Close()
+
+	created := 0
+	for i := 1; i <= *count; i++ {
+		email := fmt.Sprintf("demo%d@example.com", i)
+		_, err = createUser(context.Background(), appConfig, storages.Users, email, fmt.Sprintf("demo%d", i), *password, entity.RoleUser)
+		if errors.Is(err, errorz.EmailAlreadyTaken) || errors.Is(err, errorz.UsernameAlreadyTaken) {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", email, err)
+		}
+		created++
+	}
+
+	fmt.Printf("Created %d demo users, %d already existed\n", created, *count-created)
+	return nil
+}
+
+// createUser is a function that creates a user with a verified email, checking the same rules as registration.
+func createUser(ctx context.Context, appConfig *config.Config, users service.UserStorage, email, name, password, role string) (*entity.User, error) {
+	if _, err := mail.ParseAddress(email); err != nil {
+		return nil, fmt.Errorf("invalid email %q", email)
+	}
+	if violations := appConfig.UsernameRules.Check(name); len(violations) > 0 {
+		return nil, errorz.Validation("invalid username", violations)
+	}
+	if err := appConfig.PasswordPolicy.Validate(password, email, name); err != nil {
+		return nil, err
+	}
+
+	newUser := entity.User{
+		Email:         email,
+		Username:      name,
+		VerifiedEmail: true,
+		Role:          role,
+	}
+	if err := newUser.SetPassword(appConfig.PasswordHasher, password); err != nil {
+		return nil, err
+	}
+
+	userService := service.NewUserService(users)
+	return userService.Create(ctx, newUser)
+}
@@ -19,7 +19,371 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-      "/user/login": {
+        "/admin/log-levels": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "List the current and the configured log level of every app component",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List log levels",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.LogLevelReturn"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/log-levels/{component}": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Temporarily change the log level of a component, the configured level is restored after the duration",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Set log level",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Component: app, http, db, email or auth",
+                        "name": "component",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Level and duration",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.LogLevelUpdate"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.LogLevelReturn"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "List users with their email delivery state",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "List users",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Page size, 1 to 100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 0,
+                        "description": "Page offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/dto.AdminUserReturn"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/users/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Get a user with the email delivery state and the latest delivery events",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Get user",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.AdminUserReturn"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Problem"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/healthz": {
+            "get": {
+                "description": "Report that the process is alive, dependencies aren't checked. Served at the root of the server, not under the base path",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Liveness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Health"
+                        }
+                    }
+                }
+            }
+        },
+        "/readyz": {
+            "get": {
+                "description": "Report whether the app can serve requests: the storage, the email provider and other dependencies are checked, results are cached for a few seconds. Check errors may reveal hosts and are returned only in debug mode. Served at the root of the server, not under the base path",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness probe",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Health"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Health"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/available/email": {
+            "get": {
+                "description": "Check whether an email can be used for registration, emails are compared case-insensitively",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Check email availability",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Email to check",
+                        "name": "value",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Availability"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/available/username": {
+            "get": {
+                "description": "Check whether a username can be used for registration, usernames are compared case-insensitively after NFKC normalization",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Check username availability",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Username to check",
+                        "name": "value",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Availability"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/login": {
             "post": {
                 "description": "Login to existing user account using his email, username and password. Returns his ID, email, username, verifiedEmail boolean variable and role",
                 "consumes": [
@@ -31,15 +395,109 @@ const docTemplate = `{
                 "tags": [
                     "user"
                 ],
-                "summary": "Login to existing user account.",
+                "summary": "Login to existing user account.",
+                "parameters": [
+                    {
+                        "description": "User login body object",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserLogin"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.UserRegisterResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/logout": {
+            "post": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Revoke all refresh and access tokens of the user, requests with them are rejected from then on",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Logout from user account",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.HTTPStatus"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/user/password": {
+            "put": {
+                "security": [
+                    {
+                        "Bearer": []
+                    }
+                ],
+                "description": "Change the password of the authenticated user, the new password must meet the password policy",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "user"
+                ],
+                "summary": "Change user password",
                 "parameters": [
                     {
-                        "description": "User login body object",
+                        "description": "Current and new passwords",
                         "name": "body",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/dto.UserLogin"
+                            "$ref": "#/definitions/dto.UserChangePassword"
                         }
                     }
                 ],
@@ -47,31 +505,25 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dto.UserRegisterResponse"
+                            "$ref": "#/definitions/dto.HTTPStatus"
                         }
                     },
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/dto.HTTPError"
-                        }
-                    },
-                    "403": {
-                        "description": "Forbidden",
-                        "schema": {
-                            "$ref": "#/definitions/dto.HTTPError"
+                            "$ref": "#/definitions/dto.Problem"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/dto.HTTPError"
+                            "$ref": "#/definitions/dto.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/dto.HTTPError"
+                            "$ref": "#/definitions/dto.Problem"
                         }
                     }
                 }
@@ -92,7 +544,7 @@ const docTemplate = `{
                 "summary": "Refresh the access token",
                 "parameters": [
                     {
-                        "description": "Access token object",
+                        "description": "Refresh token object",
                         "name": "body",
                         "in": "body",
                         "required": true,
@@ -111,19 +563,19 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/dto.HTTPError"
+                            "$ref": "#/definitions/dto.Problem"
                         }
                     },
-                    "403": {
-                        "description": "Forbidden",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/dto.HTTPError"
+                            "$ref": "#/definitions/dto.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/dto.HTTPError"
+                            "$ref": "#/definitions/dto.Problem"
                         }
                     }
                 }
@@ -163,13 +615,19 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/dto.HTTPError"
+                            "$ref": "#/definitions/dto.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/dto.HTTPError"
+                            "$ref": "#/definitions/dto.Problem"
                         }
                     }
                 }
@@ -214,25 +672,103 @@ const docTemplate = `{
                     "400": {
                         "description": "Bad Request",
                         "schema": {
-                            "$ref": "#/definitions/dto.HTTPError"
+                            "$ref": "#/definitions/dto.Problem"
                         }
                     },
                     "401": {
                         "description": "Unauthorized",
                         "schema": {
-                            "$ref": "#/definitions/dto.HTTPError"
+                            "$ref": "#/definitions/dto.Problem"
                         }
                     },
                     "403": {
                         "description": "Forbidden",
                         "schema": {
-                            "$ref": "#/definitions/dto.HTTPError"
+                            "$ref": "#/definitions/dto.Problem"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Problem"
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/email": {
+            "post": {
+                "description": "Receive bounce, complaint and delivered events from the email provider. The body must be signed with HMAC-SHA256 of \"\u003cX-Webhook-Timestamp\u003e.\u003cbody\u003e\" using the shared webhook secret. Events of a batch are stored all together or none of them, so a failed batch can be retried",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "webhook"
+                ],
+                "summary": "Receive email delivery events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Unix timestamp the request was signed at",
+                        "name": "X-Webhook-Timestamp",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Request signature in sha256=\u003chex\u003e format",
+                        "name": "X-Webhook-Signature",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Delivery events",
+                        "name": "body",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.EmailWebhookPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dto.HTTPStatus"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Problem"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Problem"
                         }
                     },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
-                            "$ref": "#/definitions/dto.HTTPError"
+                            "$ref": "#/definitions/dto.Problem"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/dto.Problem"
                         }
                     }
                 }
@@ -240,6 +776,53 @@ const docTemplate = `{
         }
     },
     "definitions": {
+        "dto.AdminUserReturn": {
+            "type": "object",
+            "properties": {
+                "email": {
+                    "description": "User's email",
+                    "type": "string",
+                    "example": "example@gmail.com"
+                },
+                "email_delivery_state": {
+                    "description": "Email delivery state: unknown, delivered, bounced or complained",
+                    "type": "string",
+                    "example": "bounced"
+                },
+                "email_delivery_updated_at": {
+                    "description": "Time of the last delivery state change",
+                    "type": "string",
+                    "example": "2024-12-08T10:00:12Z"
+                },
+                "email_events": {
+                    "description": "Latest email delivery events",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.EmailEventReturn"
+                    }
+                },
+                "id": {
+                    "description": "User ID",
+                    "type": "string",
+                    "example": "123"
+                },
+                "role": {
+                    "description": "User's role (e.g. \"Client\", \"Manager\" etc)",
+                    "type": "string",
+                    "example": "manager"
+                },
+                "username": {
+                    "description": "User's username",
+                    "type": "string",
+                    "example": "linuxflight"
+                },
+                "verified_email": {
+                    "description": "Boll variable showing, whether user's email is verified or not",
+                    "type": "boolean",
+                    "example": true
+                }
+            }
+        },
         "dto.AuthTokens": {
             "type": "object",
             "properties": {
@@ -261,18 +844,103 @@ const docTemplate = `{
                 }
             }
         },
-        "dto.HTTPError": {
+        "dto.Availability": {
             "type": "object",
             "properties": {
-                "code": {
-                    "description": "HTTP error code",
-                    "type": "integer",
-                    "example": 400
+                "available": {
+                    "description": "Whether the value can be used for registration",
+                    "type": "boolean",
+                    "example": false
                 },
-                "message": {
-                    "description": "Error message",
+                "reason": {
+                    "description": "Why the value can't be used: taken, reserved or invalid",
+                    "type": "string",
+                    "example": "taken"
+                },
+                "value": {
+                    "description": "Checked value",
+                    "type": "string",
+                    "example": "linuxflight"
+                }
+            }
+        },
+        "dto.EmailEventReturn": {
+            "type": "object",
+            "properties": {
+                "message_id": {
+                    "description": "Provider message ID",
+                    "type": "string",
+                    "example": "a1b2c3"
+                },
+                "occurred_at": {
+                    "description": "Time the event happened",
+                    "type": "string",
+                    "example": "2024-12-08T10:00:12Z"
+                },
+                "reason": {
+                    "description": "Provider supplied reason",
+                    "type": "string",
+                    "example": "550 mailbox does not exist"
+                },
+                "type": {
+                    "description": "Event type",
+                    "type": "string",
+                    "example": "bounce"
+                }
+            }
+        },
+        "dto.EmailWebhookEvent": {
+            "type": "object",
+            "required": [
+                "email",
+                "type"
+            ],
+            "properties": {
+                "email": {
+                    "description": "Recipient address",
+                    "type": "string",
+                    "example": "example@gmail.com"
+                },
+                "message_id": {
+                    "description": "Provider message ID",
+                    "type": "string",
+                    "example": "a1b2c3"
+                },
+                "reason": {
+                    "description": "Provider supplied reason, if any",
                     "type": "string",
-                    "example": "you are retard"
+                    "example": "550 mailbox does not exist"
+                },
+                "timestamp": {
+                    "description": "Time the event happened, defaults to receive time",
+                    "type": "string",
+                    "example": "2024-12-08T10:00:12Z"
+                },
+                "type": {
+                    "description": "Event type: bounce, complaint or delivered",
+                    "type": "string",
+                    "enum": [
+                        "bounce",
+                        "complaint",
+                        "delivered"
+                    ],
+                    "example": "bounce"
+                }
+            }
+        },
+        "dto.EmailWebhookPayload": {
+            "type": "object",
+            "required": [
+                "events"
+            ],
+            "properties": {
+                "events": {
+                    "description": "Delivery events",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/dto.EmailWebhookEvent"
+                    }
                 }
             }
         },
@@ -287,6 +955,171 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.Health": {
+            "type": "object",
+            "properties": {
+                "checked_at": {
+                    "description": "Time of the checks, results are cached for a few seconds",
+                    "type": "string",
+                    "example": "2024-12-08T10:00:00Z"
+                },
+                "checks": {
+                    "description": "Dependency checks, only for readiness",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.HealthCheck"
+                    }
+                },
+                "status": {
+                    "description": "ok or unavailable",
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "dto.HealthCheck": {
+            "type": "object",
+            "properties": {
+                "duration_ms": {
+                    "description": "Check duration in milliseconds",
+                    "type": "number",
+                    "example": 1.5
+                },
+                "error": {
+                    "description": "Error of a failed check, only in debug mode",
+                    "type": "string",
+                    "example": "timeout"
+                },
+                "name": {
+                    "description": "the storage (postgres, sqlite or memory), email, redis or shutdown",
+                    "type": "string",
+                    "example": "postgres"
+                },
+                "status": {
+                    "description": "ok or unavailable",
+                    "type": "string",
+                    "example": "ok"
+                }
+            }
+        },
+        "dto.LogLevelReturn": {
+            "type": "object",
+            "properties": {
+                "component": {
+                    "description": "Component: app, http, db, email or auth",
+                    "type": "string",
+                    "example": "db"
+                },
+                "configured": {
+                    "description": "Level from the config, restored at revert_at",
+                    "type": "string",
+                    "example": "info"
+                },
+                "level": {
+                    "description": "Current level",
+                    "type": "string",
+                    "example": "debug"
+                },
+                "revert_at": {
+                    "description": "Expiration of a temporary level",
+                    "type": "string",
+                    "example": "2024-12-08T10:15:00Z"
+                }
+            }
+        },
+        "dto.LogLevelUpdate": {
+            "type": "object",
+            "required": [
+                "level"
+            ],
+            "properties": {
+                "duration": {
+                    "description": "Time until the configured level is restored, 15m by default, 24h at most",
+                    "type": "string",
+                    "example": "15m"
+                },
+                "level": {
+                    "description": "Required, debug, info, warn or error",
+                    "type": "string",
+                    "enum": [
+                        "debug",
+                        "info",
+                        "warn",
+                        "error"
+                    ],
+                    "example": "debug"
+                }
+            }
+        },
+        "dto.Problem": {
+            "type": "object",
+            "properties": {
+                "detail": {
+                    "description": "Explanation of this occurrence of the problem",
+                    "type": "string",
+                    "example": "validation failed"
+                },
+                "errors": {
+                    "description": "Field-level validation problems",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ProblemField"
+                    }
+                },
+                "instance": {
+                    "description": "URI reference of the request that caused the problem",
+                    "type": "string",
+                    "example": "/api/v1/user/login"
+                },
+                "request_id": {
+                    "description": "X-Request-ID of the request, to be quoted in support requests",
+                    "type": "string",
+                    "example": "6c0f5ad1-6277-4110-ab51-9a7828c2a711"
+                },
+                "status": {
+                    "description": "HTTP status code",
+                    "type": "integer",
+                    "example": 400
+                },
+                "title": {
+                    "description": "Short summary of the problem type",
+                    "type": "string",
+                    "example": "Bad Request"
+                },
+                "type": {
+                    "description": "URI reference identifying the problem type",
+                    "type": "string",
+                    "example": "urn:problem-type:validation"
+                }
+            }
+        },
+        "dto.ProblemField": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "description": "JSON name of the invalid field",
+                    "type": "string",
+                    "example": "password"
+                },
+                "message": {
+                    "description": "Human-readable description of the problem",
+                    "type": "string",
+                    "example": "password must be at least 8 characters long"
+                },
+                "params": {
+                    "description": "Params of the rule, e.g. limits",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "rule": {
+                    "description": "Name of the failed validation rule",
+                    "type": "string",
+                    "example": "password"
+                }
+            }
+        },
         "dto.Token": {
             "type": "object",
             "properties": {
@@ -302,6 +1135,25 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.UserChangePassword": {
+            "type": "object",
+            "required": [
+                "new_password",
+                "old_password"
+            ],
+            "properties": {
+                "new_password": {
+                    "description": "New password, must meet the password policy",
+                    "type": "string",
+                    "example": "NewPassword1234"
+                },
+                "old_password": {
+                    "description": "Current password",
+                    "type": "string",
+                    "example": "Password1234"
+                }
+            }
+        },
         "dto.UserCode": {
             "type": "object",
             "required": [
@@ -321,10 +1173,14 @@ const docTemplate = `{
             ],
             "properties": {
                 "email": {
-                    "type": "string"
+                    "description": "User's email, must be valid email address",
+                    "type": "string",
+                    "example": "example@gmail.com"
                 },
                 "password": {
-                    "type": "string"
+                    "description": "User's password",
+                    "type": "string",
+                    "example": "Password1234"
                 }
             }
         },
@@ -342,7 +1198,7 @@ const docTemplate = `{
                     "example": "example@gmail.com"
                 },
                 "password": {
-                    "description": "Required, password must meet certain requirements: must has upper case letters, lower case letters and digits",
+                    "description": "Required, password must meet the password policy",
                     "type": "string",
                     "example": "Password1234"
                 },
@@ -1,111 +1,405 @@
 package config
 
 import (
+	"database/sql"
 	"fmt"
-	"github.com/spf13/viper"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	gormLogger "gorm.io/gorm/logger"
-	"log"
-	"os"
+	"sync"
+	"sync/atomic"
 	"time"
 	postgresRepo "webTemplate/internal/adapters/database/postgres"
 	"webTemplate/internal/adapters/logger"
+	"webTemplate/internal/domain/common/secret"
+	"webTemplate/internal/domain/utils/password"
+	"webTemplate/internal/domain/utils/timezone"
+	"webTemplate/internal/domain/utils/username"
 )
 
+// Config is a struct that contains the typed settings of config.yaml and the components built from them.
 type Config struct {
-	Database *gorm.DB
-	Maileroo MailerooConfig
+	Service  ServiceConfig  `mapstructure:"service"`
+	Security SecurityConfig `mapstructure:"security"`
+	Roles    Roles          `mapstructure:"roles"`
+	Settings SettingsConfig `mapstructure:"settings"`
+
+	Database       *gorm.DB         `mapstructure:"-"`
+	Replicas       []*sql.DB        `mapstructure:"-"` // Pools of the read-only replicas, see ConnectReplicas
+	PasswordPolicy *password.Policy `mapstructure:"-"`
+	PasswordHasher *password.Hasher `mapstructure:"-"`
+	UsernameRules  *username.Rules  `mapstructure:"-"`
+
+	// live contains the current reloadable settings, see Live and Watch
+	live        atomic.Pointer[Reloadable]
+	reloadMu    sync.Mutex
+	values      map[string]interface{}
+	subscribers []func(live *Reloadable)
 }
 
-type MailerooConfig struct {
-	SendingApiKey      string
-	VerificationApiKey string
-	FromEmail          string
+type ServiceConfig struct {
+	Database    DatabaseConfig    `mapstructure:"database"`
+	Backend     BackendConfig     `mapstructure:"backend"`
+	EmailOutbox EmailOutboxConfig `mapstructure:"email-outbox"`
+	Health      HealthConfig      `mapstructure:"health"`
+	Maileroo    MailerooConfig    `mapstructure:"maileroo"`
 }
 
-func initConfig() {
-	viper.SetConfigName("config")
-	viper.SetConfigType("yaml")
-	viper.AddConfigPath(".")
+type DatabaseConfig struct {
+	Host     string        `mapstructure:"host" validate:"required"`
+	User     string        `mapstructure:"user" validate:"required"`
+	Password secret.Secret `mapstructure:"password"`
+	Port     int           `mapstructure:"port" validate:"min=1,max=65535"`
+	Name     string        `mapstructure:"name" validate:"required"`
+	SSLMode  string        `mapstructure:"ssl-mode" validate:"oneof=disable allow prefer require verify-ca verify-full"`
+	Pool     PoolConfig    `mapstructure:"pool"`
+	// QueryTimeout is the timeout of queries whose context has no deadline, 0 disables it
+	QueryTimeout time.Duration `mapstructure:"query-timeout" validate:"gte=0"`
+	ConnectRetry RetryConfig   `mapstructure:"connect-retry"`
+	// Replicas are read-only copies of the database, they use the credentials and the pool settings of the primary
+	Replicas []ReplicaConfig `mapstructure:"replicas" validate:"dive"`
+	// StickyPrimary is the time reads of a client go to the primary after its write, so it sees its own changes
+	StickyPrimary time.Duration `mapstructure:"sticky-primary" validate:"gte=0"`
+	// Storage is where users and tokens are kept: postgres (by default), sqlite or memory, the others are for tests and local development
+	Storage    string `mapstructure:"storage" validate:"omitempty,oneof=postgres sqlite memory"`
+	SQLitePath string `mapstructure:"sqlite-path" validate:"required_if=Storage sqlite"`
+}
 
-	if err := viper.ReadInConfig(); err != nil {
-		log.Panicf("failed to read config: %v", err)
-	}
+type ReplicaConfig struct {
+	Host string `mapstructure:"host" validate:"required"`
+	Port int    `mapstructure:"port" validate:"min=1,max=65535"`
 }
 
-func Configure() *Config {
-	initConfig()
+// PoolConfig is a struct that contains the connection pool settings, zero values mean no limit.
+type PoolConfig struct {
+	MaxOpenConns    int           `mapstructure:"max-open-conns" validate:"gte=0"`
+	MaxIdleConns    int           `mapstructure:"max-idle-conns" validate:"gte=0"`
+	ConnMaxLifetime time.Duration `mapstructure:"conn-max-lifetime" validate:"gte=0"`
+	ConnMaxIdleTime time.Duration `mapstructure:"conn-max-idle-time" validate:"gte=0"`
+}
 
-	logger.New(
-		viper.GetBool("settings.debug"),
-		viper.GetString("settings.timezone"),
-	)
-	logger.Log.Debugf("Debug mode: %t", viper.GetBool("settings.debug"))
+// RetryConfig is a struct that contains the settings of connecting at startup, while the database is not up yet.
+type RetryConfig struct {
+	Attempts   int           `mapstructure:"attempts" validate:"min=1"`
+	Backoff    time.Duration `mapstructure:"backoff" validate:"gt=0"`                 // Delay after the first failure, doubled after each next one
+	MaxBackoff time.Duration `mapstructure:"max-backoff" validate:"gtefield=Backoff"` // Maximum delay between attempts
+}
 
-	// Initialize database
-	logger.Log.Info("Initializing database...")
-	logger.Log.Debug("Configuring database logger")
-	var gormConfig *gorm.Config
-	if viper.GetBool("settings.debug") {
-		newLogger := gormLogger.New(
-			log.New(os.Stdout, "\r\n", log.LstdFlags),
-			gormLogger.Config{
-				SlowThreshold: time.Second,
-				LogLevel:      gormLogger.Info,
-				Colorful:      true,
-			},
-		)
-		gormConfig = &gorm.Config{
-			Logger: newLogger,
-		}
+type BackendConfig struct {
+	Certificate CertificateConfig `mapstructure:"certificate"`
+	Port        int               `mapstructure:"port" validate:"min=1,max=65535"`
+	JWT         JWTConfig         `mapstructure:"jwt"`
+	CORS        CORSConfig        `mapstructure:"cors"`
+	RateLimit   RateLimitConfig   `mapstructure:"rate-limit"`
+	// ShutdownTimeout is the time to drain requests and stop workers after SIGINT or SIGTERM
+	ShutdownTimeout time.Duration `mapstructure:"shutdown-timeout" validate:"gt=0"`
+	Metrics         MetricsConfig `mapstructure:"metrics"`
+}
+
+type MetricsConfig struct {
+	Enabled bool `mapstructure:"enabled"` // Serves prometheus metrics at /metrics
+	// Token is required as "Authorization: Bearer <token>" if set
+	Token secret.Secret `mapstructure:"token"`
+}
+
+type CORSConfig struct {
+	// AllowOrigins is a list of origins like "https://example.com", "*" allows any origin
+	AllowOrigins []string `mapstructure:"allow-origins" validate:"required,dive,required"`
+}
+
+type RateLimitConfig struct {
+	Enabled bool          `mapstructure:"enabled"`
+	Max     int           `mapstructure:"max" validate:"min=1"` // Requests per window and client IP
+	Window  time.Duration `mapstructure:"window" validate:"gt=0"`
+}
+
+type CertificateConfig struct {
+	CertFile string `mapstructure:"cert-file"`
+	KeyFile  string `mapstructure:"key-file"`
+}
+
+type JWTConfig struct {
+	Secret secret.Secret `mapstructure:"secret" validate:"required"`
+	// AccessTokenExpiration and RefreshTokenExpiration accept durations like "30m", bare numbers are minutes
+	AccessTokenExpiration  time.Duration `mapstructure:"access-token-expiration" validate:"gt=0"`
+	RefreshTokenExpiration time.Duration `mapstructure:"refresh-token-expiration" validate:"gt=0"`
+}
+
+type EmailOutboxConfig struct {
+	PollInterval time.Duration `mapstructure:"poll-interval" validate:"gt=0"`
+	BatchSize    int           `mapstructure:"batch-size" validate:"min=1"`
+	MaxAttempts  int           `mapstructure:"max-attempts" validate:"min=1"`
+}
+
+type HealthConfig struct {
+	CheckTimeout time.Duration `mapstructure:"check-timeout" validate:"gt=0"` // Timeout of a single readiness check
+	CacheTTL     time.Duration `mapstructure:"cache-ttl" validate:"gte=0"`    // Time readiness results are reused
+	// ShutdownDelay is the time /readyz reports "unavailable" before the server stops accepting connections
+	ShutdownDelay time.Duration `mapstructure:"shutdown-delay" validate:"gte=0"`
+	// RedisAddress is the host:port of a redis server checked by /readyz, no check if empty
+	RedisAddress string `mapstructure:"redis-address" validate:"omitempty,hostname_port"`
+}
+
+type SecurityConfig struct {
+	PasswordPolicy  PasswordPolicyConfig  `mapstructure:"password-policy"`
+	Username        UsernameConfig        `mapstructure:"username"`
+	PasswordHashing PasswordHashingConfig `mapstructure:"password-hashing"`
+}
+
+type PasswordPolicyConfig struct {
+	MinLength            int     `mapstructure:"min-length" validate:"min=1"`
+	MaxLength            int     `mapstructure:"max-length" validate:"min=1"`
+	RequireUpper         bool    `mapstructure:"require-upper"`
+	RequireLower         bool    `mapstructure:"require-lower"`
+	RequireDigit         bool    `mapstructure:"require-digit"`
+	RequireSymbol        bool    `mapstructure:"require-symbol"`
+	DisallowPersonalInfo bool    `mapstructure:"disallow-personal-info"`
+	MinStrength          float64 `mapstructure:"min-strength" validate:"min=0"`
+	BreachedCheck        bool    `mapstructure:"breached-check"`
+	BreachedFile         string  `mapstructure:"breached-file"`
+}
+
+type UsernameConfig struct {
+	MinLength      int      `mapstructure:"min-length" validate:"min=1"`
+	MaxLength      int      `mapstructure:"max-length" validate:"min=1"`
+	AllowedClasses []string `mapstructure:"allowed-classes" validate:"dive,oneof=letters digits"`
+	AllowedSymbols string   `mapstructure:"allowed-symbols"`
+	Reserved       []string `mapstructure:"reserved"`
+}
+
+type PasswordHashingConfig struct {
+	Algorithm  string         `mapstructure:"algorithm" validate:"oneof=bcrypt argon2id"`
+	BcryptCost int            `mapstructure:"bcrypt-cost"`
+	Argon2id   Argon2idConfig `mapstructure:"argon2id"`
+}
+
+type Argon2idConfig struct {
+	Memory      uint32 `mapstructure:"memory"`
+	Iterations  uint32 `mapstructure:"iterations"`
+	Parallelism uint8  `mapstructure:"parallelism"`
+}
+
+type SettingsConfig struct {
+	Debug     bool `mapstructure:"debug"`
+	ListenTLS bool `mapstructure:"listen-tls"`
+	// Timezone is an IANA zone name like "Europe/Moscow" or an offset like "GMT+3", it is parsed into Zone
+	Timezone string         `mapstructure:"timezone"`
+	Zone     *timezone.Zone `mapstructure:"-"`
+	Logging  LoggingConfig  `mapstructure:"logging"`
+	// LogLevel overrides the level set by Debug
+	LogLevel string `mapstructure:"log-level" validate:"omitempty,oneof=debug info warn error"`
+	// LogLevels overrides LogLevel for single components, e.g. {"db": "warn"}
+	LogLevels map[string]string `mapstructure:"log-levels" validate:"dive,keys,oneof=app http db email auth,endkeys,omitempty,oneof=debug info warn error"`
+}
+
+type LoggingConfig struct {
+	Format  string   `mapstructure:"format" validate:"oneof=console json"`
+	Outputs []string `mapstructure:"outputs" validate:"min=1,dive,required"` // "stdout", "stderr" or file paths
+	// RedactKeys are masked log field keys, a key matches any field key containing it, e.g. "token" masks "refresh_token"
+	RedactKeys []string `mapstructure:"redact-keys" validate:"dive,required"`
+	// SQLParams logs the values bound to queries instead of masking them, use only with test data
+	SQLParams bool `mapstructure:"sql-params"`
+}
+
+// MailerooConfig is a struct that contains the maileroo api settings, usually set from the environment.
+type MailerooConfig struct {
+	SendingApiKey      secret.Secret `mapstructure:"sending-api-key" validate:"required"`
+	VerificationApiKey secret.Secret `mapstructure:"verification-api-key" validate:"required"`
+	FromEmail          string        `mapstructure:"from-email" validate:"required,email"`
+	// WebhookSecret is optional, email delivery webhooks are rejected without it
+	WebhookSecret secret.Secret `mapstructure:"webhook-secret"`
+}
+
+// Configure is a function that loads and validates the config, then initializes the logger from it.
+// The database is connected separately by ConnectDatabase, commands like "config check" don't need it.
+/*
+ * path string - the config file path, see Path
+ */
+func Configure(path string) (*Config, error) {
+	cfg, errLoad := Load(path)
+	if errLoad != nil {
+		return nil, fmt.Errorf("failed to load config: %w", errLoad)
 	}
+	if errLogger := cfg.InitLogger(); errLogger != nil {
+		return nil, errLogger
+	}
+	return cfg, nil
+}
 
-	logger.Log.Debug("Configuring postgres connection string")
-	dsn := fmt.Sprintf("user=%s password=%s dbname=%s host=%s port=%s sslmode=%s TimeZone=%s",
-		viper.GetString("service.database.user"),
-		viper.GetString("service.database.password"),
-		viper.GetString("service.database.name"),
-		viper.GetString("service.database.host"),
-		viper.GetString("service.database.port"),
-		viper.GetString("service.database.ssl-mode"),
-		viper.GetString("settings.timezone"),
-	)
+// InitLogger is a method that initializes the logger from settings.logging and the log levels.
+// Commands that print their own output to stdout may change the outputs of a loaded config before calling it.
+func (c *Config) InitLogger() error {
+	if errLogger := logger.New(logger.Options{
+		Debug:      c.Settings.Debug,
+		Location:   c.Settings.Zone.Location(),
+		Format:     c.Settings.Logging.Format,
+		Outputs:    c.Settings.Logging.Outputs,
+		RedactKeys: c.Settings.Logging.RedactKeys,
+	}); errLogger != nil {
+		return fmt.Errorf("failed to initialize logger: %w", errLogger)
+	}
+	if errLevel := logger.SetLevels(c.Live().LogLevel, c.Live().LogLevels); errLevel != nil {
+		return fmt.Errorf("failed to set log level: %w", errLevel)
+	}
+	logger.Log.Debugf("Debug mode: %t", c.Settings.Debug)
 
 	logger.Log.Debug("Configuring maileroo")
-	from, fromExists := os.LookupEnv("MAILEROO_FROM")
-	vKey, vKeyExists := os.LookupEnv("MAILEROO_VERIFICATION_KEY")
-	sKey, sKeyExists := os.LookupEnv("MAILEROO_SENDING_KEY")
-	logger.Log.Debugf("From: \"%s\"", from)
-	logger.Log.Debugf("VKey: \"%s\"", vKey)
-	logger.Log.Debugf("SKey: \"%s\"", sKey)
-	if !fromExists || !vKeyExists || !sKeyExists {
-		logger.Log.Panic("Maileroo configuration not found")
+	maileroo := c.Service.Maileroo
+	logger.Log.Debugf("From: \"%s\"", maileroo.FromEmail)
+	if maileroo.WebhookSecret == "" {
+		logger.Log.Warnf("%s is not set, email delivery webhooks are disabled", EnvName("service.maileroo.webhook-secret"))
 	}
 	logger.Log.Debug("Maileroo set up")
-	maileroo := MailerooConfig{
-		SendingApiKey:      sKey,
-		VerificationApiKey: vKey,
-		FromEmail:          from,
+	return nil
+}
+
+// ConnectDatabase is a method that connects to postgres and sets Database, migrations are run separately.
+// Failed attempts are retried with a growing delay, see service.database.connect-retry.
+func (c *Config) ConnectDatabase() error {
+	logger.Log.Info("Initializing database...")
+	// Queries are logged at debug level of the "db" component, see settings.log-levels
+	gormConfig := &gorm.Config{
+		TranslateError: true,
+		Logger:         logger.Gorm(time.Second, c.Settings.Logging.SQLParams),
 	}
 
-	logger.Log.Debugf("dsn: %s", dsn)
-	logger.Log.Debug("Connecting to postgres...")
-	database, errConnect := gorm.Open(postgres.Open(dsn), gormConfig)
+	dbConfig := c.Service.Database
+	logger.Log.Debugf("Connecting to postgres at %s:%d/%s as %s...", dbConfig.Host, dbConfig.Port, dbConfig.Name, dbConfig.User)
+	database, errConnect := openWithRetry(postgres.Open(dbConfig.DSN(c.Settings.Zone)), gormConfig, dbConfig.ConnectRetry)
 	if errConnect != nil {
-		logger.Log.Panicf("Failed to connect to postgres: %v", errConnect)
-	} else {
-		logger.Log.Info("Connected to postgres")
+		return fmt.Errorf("failed to connect to postgres: %w", errConnect)
+	}
+
+	sqlDB, errPool := database.DB()
+	if errPool != nil {
+		return fmt.Errorf("failed to get database pool: %w", errPool)
+	}
+	setPool(sqlDB, dbConfig.Pool)
+
+	if errTimeout := postgresRepo.RegisterQueryTimeout(database, dbConfig.QueryTimeout); errTimeout != nil {
+		return fmt.Errorf("failed to set query timeout: %w", errTimeout)
+	}
+
+	logger.Log.Info("Connected to postgres")
+	c.Database = database
+	return nil
+}
+
+// ConnectReplicas is a method that connects to the replicas of service.database.replicas and routes reads to them.
+// It is called after migrations, they need a single connection to the primary.
+func (c *Config) ConnectReplicas() error {
+	dbConfig := c.Service.Database
+	if len(dbConfig.Replicas) == 0 {
+		return nil
+	}
+
+	for _, replica := range dbConfig.Replicas {
+		replicaConfig := dbConfig
+		replicaConfig.Host = replica.Host
+		replicaConfig.Port = replica.Port
+
+		logger.Log.Debugf("Connecting to postgres replica at %s:%d...", replica.Host, replica.Port)
+		database, errConnect := openWithRetry(postgres.Open(replicaConfig.DSN(c.Settings.Zone)), &gorm.Config{}, dbConfig.ConnectRetry)
+		if errConnect != nil {
+			return fmt.Errorf("failed to connect to postgres replica %s:%d: %w", replica.Host, replica.Port, errConnect)
+		}
+		sqlDB, errPool := database.DB()
+		if errPool != nil {
+			return fmt.Errorf("failed to get replica pool: %w", errPool)
+		}
+		setPool(sqlDB, dbConfig.Pool)
+		c.Replicas = append(c.Replicas, sqlDB)
 	}
 
-	logger.Log.Info("Running migrations...")
-	if errMigrate := database.AutoMigrate(postgresRepo.Migrations...); errMigrate != nil {
-		logger.Log.Panicf("Failed to run migrations: %v", errMigrate)
+	if errResolver := postgresRepo.UseReplicas(c.Database, c.Replicas, dbConfig.StickyPrimary); errResolver != nil {
+		return fmt.Errorf("failed to route reads to replicas: %w", errResolver)
 	}
+	logger.Log.Infof("Connected to %d postgres replicas", len(c.Replicas))
+	return nil
+}
+
+// setPool is a function that applies the pool settings to a connection pool.
+func setPool(sqlDB *sql.DB, pool PoolConfig) {
+	sqlDB.SetMaxOpenConns(pool.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(pool.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(pool.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(pool.ConnMaxIdleTime)
+}
+
+// openWithRetry is a function that opens a gorm connection, retrying with a doubled delay after each failure.
+func openWithRetry(dialector gorm.Dialector, gormConfig *gorm.Config, retry RetryConfig) (*gorm.DB, error) {
+	delay := retry.Backoff
+	for attempt := 1; ; attempt++ {
+		database, err := gorm.Open(dialector, gormConfig)
+		if err == nil || attempt >= retry.Attempts {
+			return database, err
+		}
+
+		logger.Log.Warnf("Failed to connect to postgres (attempt %d of %d), retrying in %s: %v", attempt, retry.Attempts, delay, err)
+		time.Sleep(delay)
+		delay = min(delay*2, retry.MaxBackoff)
+	}
+}
+
+// DSN is a method that returns the postgres connection string of the database settings.
+// It contains the password, never log it.
+func (c DatabaseConfig) DSN(zone *timezone.Zone) string {
+	return fmt.Sprintf("user=%s password=%s dbname=%s host=%s port=%d sslmode=%s TimeZone=%s",
+		c.User,
+		c.Password.Reveal(),
+		c.Name,
+		c.Host,
+		c.Port,
+		c.SSLMode,
+		zone.Postgres(),
+	)
+}
 
-	logger.Log.Info("Database initialized")
-	return &Config{
-		Database: database,
-		Maileroo: maileroo,
+// newUsernameRules is a function that builds the username rules from the "security.username" config section.
+func newUsernameRules(cfg UsernameConfig) *username.Rules {
+	return &username.Rules{
+		MinLength:      cfg.MinLength,
+		MaxLength:      cfg.MaxLength,
+		AllowedClasses: cfg.AllowedClasses,
+		AllowedSymbols: cfg.AllowedSymbols,
+		Reserved:       cfg.Reserved,
 	}
 }
+
+// newPasswordHasher is a function that builds the password hasher from the "security.password-hashing" config section.
+func newPasswordHasher(cfg PasswordHashingConfig) *password.Hasher {
+	return &password.Hasher{
+		Algorithm:  cfg.Algorithm,
+		BcryptCost: cfg.BcryptCost,
+		Argon2: password.Argon2Params{
+			Memory:      cfg.Argon2id.Memory,
+			Iterations:  cfg.Argon2id.Iterations,
+			Parallelism: cfg.Argon2id.Parallelism,
+			SaltLength:  16,
+			KeyLength:   32,
+		},
+	}
+}
+
+// newPasswordPolicy is a function that builds the password policy from the "security.password-policy" config section.
+func newPasswordPolicy(cfg PasswordPolicyConfig) (*password.Policy, error) {
+	policy := &password.Policy{
+		MinLength:            cfg.MinLength,
+		MaxLength:            cfg.MaxLength,
+		RequireUpper:         cfg.RequireUpper,
+		RequireLower:         cfg.RequireLower,
+		RequireDigit:         cfg.RequireDigit,
+		RequireSymbol:        cfg.RequireSymbol,
+		DisallowPersonalInfo: cfg.DisallowPersonalInfo,
+		MinStrength:          cfg.MinStrength,
+	}
+
+	if cfg.BreachedCheck {
+		breached, err := password.LoadBreachedList(cfg.BreachedFile)
+		if err != nil {
+			return nil, err
+		}
+		policy.Breached = breached
+	}
+
+	return policy, nil
+}
@@ -0,0 +1,107 @@
+package config
+
+import (
+	"fmt"
+	"github.com/spf13/viper"
+	"os"
+	"reflect"
+	"strings"
+)
+
+// EnvPrefix is the prefix of environment variables overriding config keys,
+// e.g. APP_SERVICE_BACKEND_JWT_SECRET overrides "service.backend.jwt.secret".
+const EnvPrefix = "APP"
+
+// secretFileSuffix is the suffix of environment variables holding a path to a file with the value of a key,
+// e.g. APP_SERVICE_DATABASE_PASSWORD_FILE=/run/secrets/db_password.
+const secretFileSuffix = "_FILE"
+
+// legacyEnv is a map of config keys to the environment variables they were read from before APP_ overrides.
+var legacyEnv = map[string]string{
+	"service.maileroo.sending-api-key":      "MAILEROO_SENDING_KEY",
+	"service.maileroo.verification-api-key": "MAILEROO_VERIFICATION_KEY",
+	"service.maileroo.from-email":           "MAILEROO_FROM",
+	"service.maileroo.webhook-secret":       "MAILEROO_WEBHOOK_SECRET",
+}
+
+// EnvName is a function that returns the environment variable overriding a config key.
+func EnvName(key string) string {
+	return EnvPrefix + "_" + strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(key))
+}
+
+// bindEnv is a function that binds every key of Config to its APP_ environment variable,
+// so keys missing from the config files can be set from the environment too.
+func bindEnv(v *viper.Viper) error {
+	for _, key := range configKeys(reflect.TypeOf(Config{}), "") {
+		envNames := []string{key, EnvName(key)}
+		if legacy, ok := legacyEnv[key]; ok {
+			envNames = append(envNames, legacy)
+		}
+		if err := v.BindEnv(envNames...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applySecretFiles is a function that sets keys from the files named by their APP_..._FILE environment variables.
+// A file takes precedence over the config files and the plain environment variable, trailing newlines are trimmed.
+func applySecretFiles(v *viper.Viper) error {
+	for _, key := range configKeys(reflect.TypeOf(Config{}), "") {
+		path, ok := os.LookupEnv(EnvName(key) + secretFileSuffix)
+		if !ok || path == "" {
+			continue
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("read %s%s: %w", EnvName(key), secretFileSuffix, err)
+		}
+		v.Set(key, strings.TrimRight(string(content), "\r\n"))
+	}
+	return nil
+}
+
+// configKeys is a function that lists the dotted keys of all scalar and slice fields of a config struct.
+// Maps like "roles" can't be expressed as a single variable and are only read from the config files.
+func configKeys(t reflect.Type, prefix string) []string {
+	var keys []string
+	walkConfig(reflect.New(t).Elem(), prefix, func(key string, value reflect.Value) {
+		if value.Kind() != reflect.Map {
+			keys = append(keys, key)
+		}
+	})
+	return keys
+}
+
+// configValues is a function that returns the values of all config keys, maps are returned as a whole.
+func configValues(cfg *Config) map[string]interface{} {
+	values := make(map[string]interface{})
+	walkConfig(reflect.ValueOf(cfg).Elem(), "", func(key string, value reflect.Value) {
+		values[key] = value.Interface()
+	})
+	return values
+}
+
+// walkConfig is a function that calls fn with the dotted key and the value of every leaf field of a config struct.
+// Fields without a mapstructure tag or tagged "-" are skipped.
+func walkConfig(v reflect.Value, prefix string, fn func(key string, value reflect.Value)) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		name := t.Field(i).Tag.Get("mapstructure")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		key := name
+		if prefix != "" {
+			key = prefix + "." + name
+		}
+
+		if field := v.Field(i); field.Kind() == reflect.Struct {
+			walkConfig(field, key, fn)
+		} else {
+			fn(key, field)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"github.com/go-playground/validator/v10"
+	"github.com/mitchellh/mapstructure"
+	"github.com/spf13/viper"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"webTemplate/internal/domain/utils/timezone"
+)
+
+// DefaultJWTSecret is the JWT secret shipped in the example config.yaml, it is refused outside debug mode.
+const DefaultJWTSecret = "super-strong-secret"
+
+// PlaceholderWebhookSecret is the webhook secret .env used to ship, it is refused outside debug mode.
+const PlaceholderWebhookSecret = "SECRET"
+
+// DefaultPath is the config file used when neither the --config flag nor APP_CONFIG is set.
+const DefaultPath = "./config.yaml"
+
+// Path is a function that resolves the config file path: the flag value, then APP_CONFIG, then DefaultPath.
+func Path(flagValue string) string {
+	if flagValue != "" {
+		return flagValue
+	}
+	if path := os.Getenv(EnvPrefix + "_CONFIG"); path != "" {
+		return path
+	}
+	return DefaultPath
+}
+
+// Load is a function that reads the config into a typed Config and validates it.
+// Unknown keys and values of a wrong type are reported instead of silently becoming zero values.
+// Values are layered from lowest to highest precedence: the base file, the profile file,
+// APP_ environment variables and files named by APP_..._FILE variables.
+/*
+ * path string - the base config file, if APP_ENV is set, "config.<APP_ENV>.yaml" next to it is merged on top
+ */
+func Load(path string) (*Config, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+
+	if profilePath := ProfilePath(path); profilePath != "" {
+		v.SetConfigFile(profilePath)
+		if err := v.MergeInConfig(); err != nil {
+			return nil, fmt.Errorf("read profile config: %w", err)
+		}
+	}
+
+	if err := bindEnv(v); err != nil {
+		return nil, fmt.Errorf("bind env: %w", err)
+	}
+	if err := applySecretFiles(v); err != nil {
+		return nil, err
+	}
+
+	cfg := &Config{}
+	decodeHook := viper.DecodeHook(mapstructure.ComposeDecodeHookFunc(
+		durationHook,
+		mapstructure.StringToSliceHookFunc(","),
+	))
+	if err := v.UnmarshalExact(cfg, decodeHook); err != nil {
+		return nil, fmt.Errorf("decode config: %w", err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	cfg.live.Store(cfg.reloadable())
+	cfg.values = configValues(cfg)
+	return cfg, nil
+}
+
+// ProfilePath is a function that returns the "config.<APP_ENV>.yaml" file next to the base config, or "" if APP_ENV is not set.
+func ProfilePath(path string) string {
+	profile := os.Getenv(EnvPrefix + "_ENV")
+	if profile == "" {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(path), fmt.Sprintf("config.%s%s", profile, filepath.Ext(path)))
+}
+
+// Validate is a method that checks required fields, ranges and secrets of the config.
+// All problems are reported at once, as "key: problem" lines. A valid settings.timezone is parsed into Settings.Zone,
+// the password policy with its breached list, the password hasher and the username rules are built.
+func (c *Config) Validate() error {
+	var problems []string
+
+	validate := validator.New()
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		return field.Tag.Get("mapstructure")
+	})
+	if err := validate.Struct(c); err != nil {
+		var validationErrors validator.ValidationErrors
+		if !errors.As(err, &validationErrors) {
+			return err
+		}
+		for _, fieldErr := range validationErrors {
+			problems = append(problems, fmt.Sprintf("%s: %s", configKey(fieldErr), ruleProblem(fieldErr)))
+		}
+	}
+
+	if !c.Settings.Debug && c.Service.Backend.JWT.Secret == DefaultJWTSecret {
+		problems = append(problems, "service.backend.jwt.secret: the default secret is only allowed in debug mode")
+	}
+	if !c.Settings.Debug && c.Service.Maileroo.WebhookSecret == PlaceholderWebhookSecret {
+		problems = append(problems, "service.maileroo.webhook-secret: the placeholder secret is only allowed in debug mode")
+	}
+	if metrics := c.Service.Backend.Metrics; !c.Settings.Debug && metrics.Enabled && metrics.Token == "" {
+		problems = append(problems, "service.backend.metrics.token: is required to enable metrics outside debug mode")
+	}
+	if storage := c.Service.Database.Storage; !c.Settings.Debug && storage != "" && storage != "postgres" {
+		problems = append(problems, fmt.Sprintf("service.database.storage: %s is only allowed in debug mode", storage))
+	}
+	if c.Service.Backend.JWT.RefreshTokenExpiration < c.Service.Backend.JWT.AccessTokenExpiration {
+		problems = append(problems, "service.backend.jwt.refresh-token-expiration: must not be less than access-token-expiration")
+	}
+	if c.Security.PasswordPolicy.MaxLength < c.Security.PasswordPolicy.MinLength {
+		problems = append(problems, "security.password-policy.max-length: must not be less than min-length")
+	}
+	if c.Security.Username.MaxLength < c.Security.Username.MinLength {
+		problems = append(problems, "security.username.max-length: must not be less than min-length")
+	}
+	if pool := c.Service.Database.Pool; pool.MaxOpenConns > 0 && pool.MaxIdleConns > pool.MaxOpenConns {
+		problems = append(problems, "service.database.pool.max-idle-conns: must not be greater than max-open-conns")
+	}
+	for _, origin := range c.Service.Backend.CORS.AllowOrigins {
+		if !validOrigin(origin) {
+			problems = append(problems, fmt.Sprintf("service.backend.cors.allow-origins: %q is not \"*\" or an origin like \"https://example.com\"", origin))
+		}
+	}
+	if zone, err := timezone.Parse(c.Settings.Timezone); err != nil {
+		problems = append(problems, fmt.Sprintf("settings.timezone: %v", err))
+	} else {
+		c.Settings.Zone = zone
+	}
+	if c.Settings.ListenTLS && (c.Service.Backend.Certificate.CertFile == "" || c.Service.Backend.Certificate.KeyFile == "") {
+		problems = append(problems, "service.backend.certificate: cert-file and key-file are required with settings.listen-tls")
+	}
+	// The components are built here, so "config check" reports everything the app would fail to start with
+	hasher := newPasswordHasher(c.Security.PasswordHashing)
+	if err := hasher.Validate(); err != nil {
+		problems = append(problems, fmt.Sprintf("security.password-hashing: %v", err))
+	} else {
+		c.PasswordHasher = hasher
+	}
+	if policy, err := newPasswordPolicy(c.Security.PasswordPolicy); err != nil {
+		problems = append(problems, fmt.Sprintf("security.password-policy.breached-file: %v", err))
+	} else {
+		c.PasswordPolicy = policy
+	}
+	c.UsernameRules = newUsernameRules(c.Security.Username)
+
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid config:\n\t%s", strings.Join(problems, "\n\t"))
+	}
+	return nil
+}
+
+// validOrigin is a function that reports whether origin is "*" or a scheme and a host without a path.
+func validOrigin(origin string) bool {
+	if origin == "*" {
+		return true
+	}
+	parsed, err := url.Parse(origin)
+	return err == nil && parsed.Scheme != "" && parsed.Host != "" && (parsed.Path == "" || parsed.Path == "/") && parsed.RawQuery == ""
+}
+
+// configKey is a function that converts a validator namespace like "Config.service.backend.port" to a config key.
+func configKey(err validator.FieldError) string {
+	namespace := err.Namespace()
+	if i := strings.Index(namespace, "."); i != -1 {
+		return namespace[i+1:]
+	}
+	return namespace
+}
+
+// ruleProblem is a function that describes a failed validation rule of a config key.
+func ruleProblem(err validator.FieldError) string {
+	switch err.Tag() {
+	case "required":
+		return "is required"
+	case "min", "gte":
+		return "must be at least " + err.Param()
+	case "max", "lte":
+		return "must be at most " + err.Param()
+	case "gt":
+		return "must be greater than " + err.Param()
+	case "oneof":
+		return "must be one of: " + err.Param()
+	default:
+		return "must satisfy " + err.Tag()
+	}
+}
+
+// durationHook is a mapstructure decode hook that parses durations like "30m" or "720h".
+// Bare numbers are minutes, the unit of the jwt expiration keys before durations were supported.
+func durationHook(from reflect.Type, to reflect.Type, data interface{}) (interface{}, error) {
+	if to != reflect.TypeOf(time.Duration(0)) {
+		return data, nil
+	}
+
+	switch value := data.(type) {
+	case int:
+		return time.Duration(value) * time.Minute, nil
+	case int64:
+		return time.Duration(value) * time.Minute, nil
+	case float64:
+		return time.Duration(value * float64(time.Minute)), nil
+	case string:
+		value = strings.TrimSpace(value)
+		if minutes, err := strconv.ParseFloat(value, 64); err == nil {
+			return time.Duration(minutes * float64(time.Minute)), nil
+		}
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid duration %q: %w", value, err)
+		}
+		return duration, nil
+	}
+	return data, nil
+}
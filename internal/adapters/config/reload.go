@@ -0,0 +1,145 @@
+package config
+
+import (
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/viper"
+	"reflect"
+	"sort"
+	"strings"
+	"webTemplate/internal/adapters/logger"
+)
+
+// reloadableKeys is a list of config keys and sections applied at runtime, changes of other keys need a restart.
+var reloadableKeys = []string{
+	"roles",
+	"settings.log-level",
+	"settings.log-levels",
+	"service.backend.cors",
+	"service.backend.rate-limit",
+}
+
+// Reloadable is a struct that contains the settings that are applied without a restart when the config changes.
+type Reloadable struct {
+	Roles     Roles
+	LogLevel  string
+	LogLevels map[string]string
+	CORS      CORSConfig
+	RateLimit RateLimitConfig
+}
+
+// Live is a method that returns the current reloadable settings, they must be read on every use instead of being copied.
+func (c *Config) Live() *Reloadable {
+	return c.live.Load()
+}
+
+// OnReload is a method that registers a function called with the new reloadable settings after they change.
+func (c *Config) OnReload(fn func(live *Reloadable)) {
+	c.reloadMu.Lock()
+	defer c.reloadMu.Unlock()
+
+	c.subscribers = append(c.subscribers, fn)
+}
+
+// Watch is a method that reloads the config whenever the base or the profile config file changes.
+// The new config is validated before anything is applied, an invalid config is logged and ignored.
+/*
+ * path string - the base config file passed to Load
+ */
+func (c *Config) Watch(path string) {
+	for _, file := range []string{path, ProfilePath(path)} {
+		if file == "" {
+			continue
+		}
+
+		v := viper.New()
+		v.SetConfigFile(file)
+		v.OnConfigChange(func(event fsnotify.Event) {
+			logger.Log.Debugf("Config file changed: %s", event.Name)
+			c.reload(path)
+		})
+		v.WatchConfig()
+	}
+	logger.Log.Infof("Watching config changes, reloadable keys: %s", strings.Join(reloadableKeys, ", "))
+}
+
+// reload is a method that loads the config again and atomically swaps the reloadable settings.
+func (c *Config) reload(path string) {
+	next, err := Load(path)
+	if err != nil {
+		logger.Log.Errorf("Config reload rejected, keeping the current config: %v", err)
+		return
+	}
+
+	c.reloadMu.Lock()
+	defer c.reloadMu.Unlock()
+
+	var applied, ignored []string
+	for _, key := range changedKeys(c.values, next.values) {
+		if isReloadable(key) {
+			applied = append(applied, key)
+		} else {
+			ignored = append(ignored, key)
+		}
+	}
+	c.values = next.values
+
+	if len(ignored) > 0 {
+		logger.Log.Warnf("Config changes need a restart to take effect: %s", strings.Join(ignored, ", "))
+	}
+	if len(applied) == 0 {
+		return
+	}
+
+	// settings.debug needs a restart, so the default log level follows the running value, not the file
+	next.Settings.Debug = c.Settings.Debug
+	live := next.reloadable()
+	if err := logger.SetLevels(live.LogLevel, live.LogLevels); err != nil {
+		logger.Log.Errorf("Failed to set log level: %v", err)
+	}
+	c.live.Store(live)
+	for _, subscriber := range c.subscribers {
+		subscriber(live)
+	}
+	logger.Log.Infof("Config reloaded, applied changes: %s", strings.Join(applied, ", "))
+}
+
+// reloadable is a method that returns the reloadable settings of the config.
+func (c *Config) reloadable() *Reloadable {
+	logLevel := c.Settings.LogLevel
+	if logLevel == "" {
+		logLevel = "info"
+		if c.Settings.Debug {
+			logLevel = "debug"
+		}
+	}
+
+	return &Reloadable{
+		Roles:     c.Roles,
+		LogLevel:  logLevel,
+		LogLevels: c.Settings.LogLevels,
+		CORS:      c.Service.Backend.CORS,
+		RateLimit: c.Service.Backend.RateLimit,
+	}
+}
+
+// isReloadable is a function that reports whether the key is one of reloadableKeys or inside one of their sections.
+func isReloadable(key string) bool {
+	for _, reloadableKey := range reloadableKeys {
+		if key == reloadableKey || strings.HasPrefix(key, reloadableKey+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// changedKeys is a function that returns the sorted keys with different values in the two configs.
+func changedKeys(previous, next map[string]interface{}) []string {
+	var keys []string
+	for key, value := range next {
+		if !reflect.DeepEqual(previous[key], value) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -1,12 +1,11 @@
 package config
 
-import (
-	"fmt"
-	"github.com/spf13/viper"
-)
+// Roles is a map of role names to the rights granted to the role, it is the "roles" config section.
+type Roles map[string][]string
 
-func RoleHasRights(role string, requiredRights []string) bool {
-	userRights := viper.GetStringSlice(fmt.Sprintf("roles.%s", role))
+// HasRights is a method that reports whether the role has every one of the required rights.
+func (r Roles) HasRights(role string, requiredRights []string) bool {
+	userRights := r[role]
 
 	rightSet := make(map[string]struct{}, len(userRights))
 	for _, right := range userRights {
@@ -0,0 +1,95 @@
+package errorhandler
+
+import (
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"net/http"
+	"webTemplate/internal/adapters/controller/api/validator"
+	"webTemplate/internal/adapters/logger"
+	"webTemplate/internal/domain/common/errorz"
+	"webTemplate/internal/domain/common/requestid"
+	"webTemplate/internal/domain/dto"
+)
+
+// ContentType is the media type of RFC 7807 problem responses.
+const ContentType = "application/problem+json"
+
+// typePrefix is the prefix of problem type URIs, followed by the domain error code.
+const typePrefix = "urn:problem-type:"
+
+// statuses is a map of domain error codes to HTTP statuses.
+var statuses = map[errorz.Code]int{
+	errorz.CodeNotFound:        fiber.StatusNotFound,
+	errorz.CodeConflict:        fiber.StatusConflict,
+	errorz.CodeUnauthorized:    fiber.StatusUnauthorized,
+	errorz.CodeForbidden:       fiber.StatusForbidden,
+	errorz.CodeValidation:      fiber.StatusBadRequest,
+	errorz.CodeTooManyRequests: fiber.StatusTooManyRequests,
+	errorz.CodeUnavailable:     fiber.StatusServiceUnavailable,
+	errorz.CodeInternal:        fiber.StatusInternalServerError,
+}
+
+// New is a function that returns the global fiber error handler, writing errors as RFC 7807 problems
+/*
+ * debug bool - if true, details of internal errors are included in responses
+ */
+func New(debug bool) fiber.ErrorHandler {
+	return func(c *fiber.Ctx, err error) error {
+		problem := resolve(err, debug, c.AcceptsLanguages(validator.Locales()...))
+		problem.Instance = c.OriginalURL()
+		problem.RequestID = requestid.FromContext(c.Context())
+
+		if problem.Status >= fiber.StatusInternalServerError {
+			logger.FromContext(c.Context()).Named(logger.ComponentHTTP).Errorf("request failed: %v", err)
+		}
+
+		return c.Status(problem.Status).JSON(problem, ContentType)
+	}
+}
+
+// resolve is a function that converts an error to a problem with a client-safe detail and localized field errors.
+func resolve(err error, debug bool, locale string) dto.Problem {
+	var domainErr *errorz.Error
+	if !errors.As(err, &domainErr) {
+		var fiberErr *fiber.Error
+		if errors.As(err, &fiberErr) {
+			return dto.Problem{
+				Type:   "about:blank",
+				Title:  http.StatusText(fiberErr.Code),
+				Status: fiberErr.Code,
+				Detail: fiberErr.Message,
+			}
+		}
+		domainErr = errorz.Internal(err)
+	}
+
+	status, ok := statuses[domainErr.Code]
+	if !ok {
+		status = fiber.StatusInternalServerError
+	}
+
+	problem := dto.Problem{
+		Type:   typePrefix + string(domainErr.Code),
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: domainErr.Message,
+	}
+	if status >= fiber.StatusInternalServerError && debug {
+		problem.Detail = domainErr.Error()
+	}
+
+	fields := domainErr.Fields
+	if locale != "" && locale != validator.DefaultLocale {
+		fields = validator.Localize(fields, locale)
+	}
+	for _, field := range fields {
+		problem.Errors = append(problem.Errors, dto.ProblemField{
+			Field:   field.Field,
+			Rule:    field.Rule,
+			Message: field.Message,
+			Params:  field.Params,
+		})
+	}
+
+	return problem
+}
@@ -0,0 +1,132 @@
+package errorhandler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"net/http/httptest"
+	"testing"
+	"webTemplate/internal/adapters/logger"
+	"webTemplate/internal/domain/common/errorz"
+	"webTemplate/internal/domain/dto"
+)
+
+func TestResolve(t *testing.T) {
+	cause := errors.New("connection refused at 10.0.0.5:5432")
+	fieldErr := errorz.Validation("validation failed", []errorz.FieldError{
+		{Field: "email", Rule: "required", Message: "email is required"},
+	})
+
+	tests := []struct {
+		name       string
+		err        error
+		debug      bool
+		wantStatus int
+		wantType   string
+		wantDetail string
+	}{
+		{"not found", errorz.New(errorz.CodeNotFound, "user not found"), false, 404, "urn:problem-type:not_found", "user not found"},
+		{"conflict", errorz.New(errorz.CodeConflict, "username taken"), false, 409, "urn:problem-type:conflict", "username taken"},
+		{"unauthorized", errorz.New(errorz.CodeUnauthorized, "invalid token"), false, 401, "urn:problem-type:unauthorized", "invalid token"},
+		{"forbidden", errorz.New(errorz.CodeForbidden, "no rights"), false, 403, "urn:problem-type:forbidden", "no rights"},
+		{"validation", fieldErr, false, 400, "urn:problem-type:validation", "validation failed"},
+		{"too many requests", errorz.New(errorz.CodeTooManyRequests, "slow down"), false, 429, "urn:problem-type:too_many_requests", "slow down"},
+		{"unavailable", errorz.New(errorz.CodeUnavailable, "maintenance"), false, 503, "urn:problem-type:unavailable", "maintenance"},
+		{"unknown code", errorz.New("teapot", "odd"), false, 500, "urn:problem-type:teapot", "odd"},
+		{"wrapped domain error", fmt.Errorf("handler: %w", errorz.New(errorz.CodeNotFound, "user not found")), false, 404, "urn:problem-type:not_found", "user not found"},
+		{"client detail keeps the cause out", errorz.Wrap(cause, errorz.CodeNotFound, "user not found"), false, 404, "urn:problem-type:not_found", "user not found"},
+		{"internal hides the cause", errorz.Internal(cause), false, 500, "urn:problem-type:internal", "internal server error"},
+		{"internal shows the cause in debug", errorz.Internal(cause), true, 500, "urn:problem-type:internal", "internal server error: " + cause.Error()},
+		{"plain error hides the cause", cause, false, 500, "urn:problem-type:internal", "internal server error"},
+		{"plain error shows the cause in debug", cause, true, 500, "urn:problem-type:internal", "internal server error: " + cause.Error()},
+		{"fiber error", fiber.ErrMethodNotAllowed, false, 405, "about:blank", "Method Not Allowed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problem := resolve(tt.err, tt.debug, "")
+			if problem.Status != tt.wantStatus {
+				t.Errorf("Status = %d, want %d", problem.Status, tt.wantStatus)
+			}
+			if problem.Type != tt.wantType {
+				t.Errorf("Type = %q, want %q", problem.Type, tt.wantType)
+			}
+			if problem.Detail != tt.wantDetail {
+				t.Errorf("Detail = %q, want %q", problem.Detail, tt.wantDetail)
+			}
+			if problem.Title == "" {
+				t.Error("Title is empty")
+			}
+		})
+	}
+}
+
+func TestResolveFields(t *testing.T) {
+	err := errorz.Validation("validation failed", []errorz.FieldError{
+		{Field: "email", Rule: "required", Message: "email is required"},
+	})
+
+	problem := resolve(err, false, "")
+	if len(problem.Errors) != 1 || problem.Errors[0].Field != "email" || problem.Errors[0].Message != "email is required" {
+		t.Errorf("Errors = %+v, want the email field", problem.Errors)
+	}
+
+	localized := resolve(err, false, "ru")
+	if len(localized.Errors) != 1 || localized.Errors[0].Message == "email is required" {
+		t.Errorf("Errors = %+v, want a localized message", localized.Errors)
+	}
+}
+
+func TestNew(t *testing.T) {
+	if logger.Log == nil {
+		if err := logger.New(logger.Options{Outputs: []string{"stderr"}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	app := fiber.New(fiber.Config{ErrorHandler: New(false)})
+	app.Get("/missing", func(c *fiber.Ctx) error {
+		return errorz.New(errorz.CodeNotFound, "user not found")
+	})
+	app.Get("/broken", func(c *fiber.Ctx) error {
+		return errors.New("secret dsn in the error")
+	})
+
+	tests := []struct {
+		path       string
+		wantStatus int
+		wantDetail string
+	}{
+		{"/missing", 404, "user not found"},
+		{"/broken", 500, "internal server error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest("GET", tt.path+"?page=2", nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if got := resp.Header.Get(fiber.HeaderContentType); got != ContentType {
+				t.Errorf("Content-Type = %q, want %q", got, ContentType)
+			}
+
+			var problem dto.Problem
+			if err = json.NewDecoder(resp.Body).Decode(&problem); err != nil {
+				t.Fatal(err)
+			}
+			if problem.Detail != tt.wantDetail {
+				t.Errorf("Detail = %q, want %q", problem.Detail, tt.wantDetail)
+			}
+			if problem.Instance != tt.path+"?page=2" {
+				t.Errorf("Instance = %q, want %q", problem.Instance, tt.path+"?page=2")
+			}
+		})
+	}
+}
@@ -2,9 +2,6 @@ package setup
 
 import (
 	"github.com/gofiber/contrib/swagger"
-	"github.com/gofiber/fiber/v2/middleware/cors"
-	"github.com/gofiber/fiber/v2/middleware/logger"
-	"github.com/spf13/viper"
 	"webTemplate/cmd/app"
 	v1 "webTemplate/internal/adapters/controller/api/v1"
 	"webTemplate/internal/adapters/controller/api/v1/middlewares"
@@ -12,7 +9,21 @@ import (
 )
 
 func Setup(app *app.App) {
-	app.Fiber.Use(cors.New(cors.ConfigDefault))
+	app.Fiber.Use(middlewares.RequestID())
+	app.Fiber.Use(middlewares.RequestLogger())
+	app.Fiber.Use(middlewares.ReadYourWrites())
+	app.Fiber.Use(middlewares.AccessLog())
+	app.Fiber.Use(middlewares.CORS(app.Config))
+
+	// Setup health probes
+	healthHandler := v1.NewHealthHandler(app)
+	healthHandler.Setup(app.Fiber)
+
+	// Setup prometheus metrics
+	if app.Config.Service.Backend.Metrics.Enabled {
+		metricsHandler := v1.NewMetricsHandler(app)
+		metricsHandler.Setup(app.Fiber)
+	}
 
 	app.Fiber.Use(swagger.New(swagger.Config{
 		BasePath: "/api/v1",
@@ -21,16 +32,24 @@ func Setup(app *app.App) {
 		Title:    "Swagger API Docs",
 	}))
 
-	if viper.GetBool("settings.debug") {
-		app.Fiber.Use(logger.New(logger.Config{TimeZone: viper.GetString("settings.timezone")}))
-	}
-
 	// Setup api v1 routes
-	apiV1 := app.Fiber.Group("/api/v1")
+	apiV1 := app.Fiber.Group("/api/v1", middlewares.RateLimit(app.Config))
 
 	middlewareHandler := middlewares.NewMiddlewareHandler(app)
 	//
 	// Setup user routes
 	userHandler := v1.NewUserHandler(app)
 	userHandler.Setup(apiV1, middlewareHandler.IsAuthenticated(auth.TokenTypeAccess))
+
+	// Setup admin routes
+	adminHandler := v1.NewAdminHandler(app)
+	adminHandler.Setup(apiV1, middlewareHandler.IsAuthenticated(auth.TokenTypeAccess, "manageUsers"))
+
+	// Setup log level routes
+	loggingHandler := v1.NewLoggingHandler(app)
+	loggingHandler.Setup(apiV1, middlewareHandler.IsAuthenticated(auth.TokenTypeAccess, "manageLogs"))
+
+	// Setup webhook routes
+	webhookHandler := v1.NewWebhookHandler(app)
+	webhookHandler.Setup(apiV1)
 }
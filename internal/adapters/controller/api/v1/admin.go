@@ -0,0 +1,138 @@
+package v1
+
+import (
+	"context"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"webTemplate/cmd/app"
+	"webTemplate/internal/adapters/controller/api/validator"
+	"webTemplate/internal/domain/common/errorz"
+	"webTemplate/internal/domain/dto"
+	"webTemplate/internal/domain/entity"
+	"webTemplate/internal/domain/service"
+)
+
+// adminEmailEventsLimit is the number of latest email events returned with a single user.
+const adminEmailEventsLimit = 20
+
+type AdminUserService interface {
+	GetByID(ctx context.Context, uuid string) (*entity.User, error)
+	GetAll(ctx context.Context, limit, offset int) ([]entity.User, error)
+}
+
+type AdminEmailEventService interface {
+	GetByUserID(ctx context.Context, userID string, limit int) ([]entity.EmailEvent, error)
+}
+
+type AdminHandler struct {
+	userService       AdminUserService
+	emailEventService AdminEmailEventService
+	validator         *validator.Validator
+}
+
+func NewAdminHandler(app *app.App) *AdminHandler {
+	return &AdminHandler{
+		userService:       service.NewUserService(app.Storages.Users),
+		emailEventService: service.NewEmailEventService(app.Storages.Transactor, app.Storages.EmailEvents, app.Storages.Users),
+		validator:         app.Validator,
+	}
+}
+
+// getUsers godoc
+// @Summary      List users
+// @Description  List users with their email delivery state
+// @Tags         admin
+// @Produce      json
+// @Security Bearer
+// @Param        limit  query  int  false  "Page size, 1 to 100"  default(10)
+// @Param        offset query  int  false  "Page offset"          default(0)
+// @Success      200  {array}   dto.AdminUserReturn
+// @Failure      400  {object}  dto.Problem
+// @Failure      401  {object}  dto.Problem
+// @Failure      403  {object}  dto.Problem
+// @Failure      500  {object}  dto.Problem
+// @Router       /admin/users [get]
+func (h AdminHandler) getUsers(c *fiber.Ctx) error {
+	limit, offset, err := h.validator.GetLimitAndOffset(c, 10, 0)
+	if err != nil {
+		return err
+	}
+
+	users, err := h.userService.GetAll(c.Context(), limit, offset)
+	if err != nil {
+		return err
+	}
+
+	response := make([]dto.AdminUserReturn, 0, len(users))
+	for i := range users {
+		response = append(response, adminUserReturn(&users[i], nil))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// getUser godoc
+// @Summary      Get user
+// @Description  Get a user with the email delivery state and the latest delivery events
+// @Tags         admin
+// @Produce      json
+// @Security Bearer
+// @Param        id   path      string  true  "User ID"
+// @Success      200  {object}  dto.AdminUserReturn
+// @Failure      401  {object}  dto.Problem
+// @Failure      403  {object}  dto.Problem
+// @Failure      404  {object}  dto.Problem
+// @Failure      500  {object}  dto.Problem
+// @Router       /admin/users/{id} [get]
+func (h AdminHandler) getUser(c *fiber.Ctx) error {
+	// No user has a malformed id, postgres would fail to cast it to uuid instead of finding nothing
+	id := c.Params("id")
+	if _, err := uuid.Parse(id); err != nil {
+		return errorz.UserNotFound.Wrap(err)
+	}
+
+	user, err := h.userService.GetByID(c.Context(), id)
+	if err != nil {
+		return err
+	}
+
+	events, err := h.emailEventService.GetByUserID(c.Context(), user.ID, adminEmailEventsLimit)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(adminUserReturn(user, events))
+}
+
+// adminUserReturn is a function that converts a user and its email events to the admin view.
+func adminUserReturn(user *entity.User, events []entity.EmailEvent) dto.AdminUserReturn {
+	response := dto.AdminUserReturn{
+		UserReturn: dto.UserReturn{
+			ID:            user.ID,
+			Email:         user.Email,
+			VerifiedEmail: user.VerifiedEmail,
+			Username:      user.Username,
+			Role:          user.Role,
+		},
+		EmailDeliveryState:     user.EmailDeliveryState,
+		EmailDeliveryUpdatedAt: user.EmailDeliveryUpdatedAt,
+	}
+
+	for _, event := range events {
+		response.EmailEvents = append(response.EmailEvents, dto.EmailEventReturn{
+			Type:       event.Type,
+			MessageID:  event.MessageID,
+			Reason:     event.Reason,
+			OccurredAt: event.OccurredAt,
+		})
+	}
+
+	return response
+}
+
+func (h AdminHandler) Setup(router fiber.Router, middleware fiber.Handler) {
+	// The middleware is set per route without a group: group middleware would also run for the /admin routes
+	// of other handlers, e.g. the log levels guarded by another right
+	router.Get("/admin/users", middleware, h.getUsers)
+	router.Get("/admin/users/:id", middleware, h.getUser)
+}
@@ -0,0 +1,41 @@
+package v1_test
+
+import (
+	"net/http"
+	"testing"
+	"webTemplate/internal/adapters/config"
+	"webTemplate/internal/domain/dto"
+	"webTemplate/internal/domain/entity"
+)
+
+func TestAdminGetUser(t *testing.T) {
+	testApp := newTestApp(t, config.Roles{"user-keeper": {"manageUsers"}})
+	user, _ := createUser(t, testApp, "someone", entity.RoleUser)
+	_, token := createUser(t, testApp, "userkeeper", "user-keeper")
+
+	tests := []struct {
+		name       string
+		id         string
+		wantStatus int
+	}{
+		{name: "existing user", id: user.ID, wantStatus: http.StatusOK},
+		{name: "missing user", id: "00000000-0000-0000-0000-000000000000", wantStatus: http.StatusNotFound},
+		{name: "malformed id", id: "not-a-uuid", wantStatus: http.StatusNotFound},
+		{name: "sql in id", id: "1%27%20OR%20%271%27%3D%271", wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := do(t, testApp, http.MethodGet, "/api/v1/admin/users/"+tt.id, token, "")
+			if status != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", status, tt.wantStatus, body)
+			}
+			if status == http.StatusOK {
+				var adminUser dto.AdminUserReturn
+				decode(t, body, &adminUser)
+				if adminUser.ID != user.ID {
+					t.Errorf("id = %q, want %q", adminUser.ID, user.ID)
+				}
+			}
+		})
+	}
+}
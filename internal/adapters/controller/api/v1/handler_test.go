@@ -0,0 +1,113 @@
+package v1_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"webTemplate/cmd/app"
+	"webTemplate/internal/adapters/config"
+	"webTemplate/internal/adapters/controller/api/setup"
+	"webTemplate/internal/adapters/database/storage"
+	"webTemplate/internal/domain/entity"
+	"webTemplate/internal/domain/service"
+	"webTemplate/internal/domain/utils/username"
+)
+
+// repoRoot is the directory of config.yaml and docs, relative to this package.
+const repoRoot = "../../../../.."
+
+// newTestApp is a function that builds the app from the example config with memory storages and all routes.
+// Roles are added to the configured ones, e.g. roles with a single right.
+func newTestApp(t *testing.T, roles config.Roles) *app.App {
+	t.Helper()
+
+	// Swagger reads ./docs when routes are set up
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.Chdir(repoRoot); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	t.Setenv("APP_SETTINGS_LOG_LEVEL", "error")
+	t.Setenv("APP_SERVICE_DATABASE_STORAGE", storage.Memory)
+	t.Setenv("APP_SERVICE_MAILEROO_SENDING_API_KEY", "test")
+	t.Setenv("APP_SERVICE_MAILEROO_VERIFICATION_API_KEY", "test")
+	t.Setenv("APP_SERVICE_MAILEROO_FROM_EMAIL", "noreply@example.com")
+
+	appConfig, err := config.Configure("config.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for role, rights := range roles {
+		appConfig.Live().Roles[role] = rights
+	}
+
+	storages, err := storage.New(appConfig.Service.Database, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	testApp := app.New(appConfig, storages)
+	setup.Setup(testApp)
+	return testApp
+}
+
+// createUser is a function that stores a verified user with the role and returns it with a stored access token.
+func createUser(t *testing.T, testApp *app.App, name, role string) (*entity.User, string) {
+	t.Helper()
+	ctx := context.Background()
+
+	email := name + "@example.com"
+	user, err := testApp.Storages.Users.Create(ctx, entity.User{
+		Email:             email,
+		Username:          name,
+		EmailCanonical:    username.CanonicalEmail(email),
+		UsernameCanonical: username.Canonical(name),
+		VerifiedEmail:     true,
+		Role:              role,
+	})
+	if err != nil {
+		t.Fatalf("Create user: %v", err)
+	}
+
+	token, err := service.NewTokenService(testApp.Storages.Tokens, testApp.Config.Service.Backend.JWT).GenerateAccessToken(ctx, user.ID)
+	if err != nil {
+		t.Fatalf("GenerateAccessToken: %v", err)
+	}
+	return user, token.Token
+}
+
+// do is a function that sends a request to the app, body is sent as json if it isn't empty.
+func do(t *testing.T, testApp *app.App, method, path, token, body string) (int, string) {
+	t.Helper()
+
+	request := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		request.Header.Set("Content-Type", "application/json")
+	}
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	response, err := testApp.Fiber.Test(request, -1)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer response.Body.Close()
+	responseBody, _ := io.ReadAll(response.Body)
+	return response.StatusCode, string(responseBody)
+}
+
+// decode is a function that decodes a json response body.
+func decode(t *testing.T, body string, v any) {
+	t.Helper()
+	if err := json.Unmarshal([]byte(body), v); err != nil {
+		t.Fatalf("decode %q: %v", body, err)
+	}
+}
@@ -0,0 +1,96 @@
+package v1
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"webTemplate/cmd/app"
+	"webTemplate/internal/adapters/database/redis"
+	"webTemplate/internal/adapters/database/storage"
+	"webTemplate/internal/domain/dto"
+	"webTemplate/internal/domain/service"
+)
+
+type HealthService interface {
+	Ready(ctx context.Context) dto.Health
+}
+
+type HealthHandler struct {
+	healthService HealthService
+	debug         bool
+}
+
+func NewHealthHandler(app *app.App) *HealthHandler {
+	healthConfig := app.Config.Service.Health
+
+	checks := []service.HealthCheck{
+		{Name: cmp.Or(app.Config.Service.Database.Storage, storage.Postgres), Check: app.Storages.Ping},
+		{Name: "email", Check: service.NewEmailService(app.Maileroo, nil).Ping},
+	}
+	for i, replica := range app.Config.Replicas {
+		checks = append(checks, service.HealthCheck{Name: fmt.Sprintf("replica-%d", i+1), Check: replica.PingContext})
+	}
+	if healthConfig.RedisAddress != "" {
+		checks = append(checks, service.HealthCheck{
+			Name:  "redis",
+			Check: func(ctx context.Context) error { return redis.Ping(ctx, healthConfig.RedisAddress) },
+		})
+	}
+
+	return &HealthHandler{
+		healthService: service.NewHealthService(
+			checks,
+			service.HealthConfig{
+				Timeout:  healthConfig.CheckTimeout,
+				CacheTTL: healthConfig.CacheTTL,
+			},
+			app.ShuttingDown,
+		),
+		debug: app.Config.Settings.Debug,
+	}
+}
+
+// liveness godoc
+// @Summary      Liveness probe
+// @Description  Report that the process is alive, dependencies aren't checked. Served at the root of the server, not under the base path
+// @Tags         health
+// @Produce      json
+// @Success      200  {object}  dto.Health
+// @Router       /healthz [get]
+func (h HealthHandler) liveness(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusOK).JSON(dto.Health{Status: dto.HealthOK})
+}
+
+// readiness godoc
+// @Summary      Readiness probe
+// @Description  Report whether the app can serve requests: the storage, the email provider and other dependencies are checked, results are cached for a few seconds. Check errors may reveal hosts and are returned only in debug mode. Served at the root of the server, not under the base path
+// @Tags         health
+// @Produce      json
+// @Success      200  {object}  dto.Health
+// @Failure      503  {object}  dto.Health
+// @Router       /readyz [get]
+func (h HealthHandler) readiness(c *fiber.Ctx) error {
+	health := h.healthService.Ready(c.Context())
+
+	checks := make([]dto.HealthCheck, 0, len(health.Checks))
+	for _, check := range health.Checks {
+		if !h.debug {
+			check.Error = ""
+		}
+		checks = append(checks, check)
+	}
+	health.Checks = checks
+
+	status := fiber.StatusOK
+	if health.Status != dto.HealthOK {
+		status = fiber.StatusServiceUnavailable
+	}
+	return c.Status(status).JSON(health)
+}
+
+// Setup is a method that registers the probes at the root of the app, outside of the rate limited api.
+func (h HealthHandler) Setup(router fiber.Router) {
+	router.Get("/healthz", h.liveness)
+	router.Get("/readyz", h.readiness)
+}
@@ -0,0 +1,114 @@
+package v1
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"slices"
+	"time"
+	"webTemplate/cmd/app"
+	"webTemplate/internal/adapters/controller/api/validator"
+	"webTemplate/internal/adapters/logger"
+	"webTemplate/internal/domain/common/errorz"
+	"webTemplate/internal/domain/dto"
+)
+
+// defaultLogLevelDuration is the time a temporary log level is kept when the request has no duration.
+const defaultLogLevelDuration = 15 * time.Minute
+
+type LoggingHandler struct {
+	validator *validator.Validator
+}
+
+func NewLoggingHandler(app *app.App) *LoggingHandler {
+	return &LoggingHandler{
+		validator: app.Validator,
+	}
+}
+
+// getLogLevels godoc
+// @Summary      List log levels
+// @Description  List the current and the configured log level of every app component
+// @Tags         admin
+// @Produce      json
+// @Security Bearer
+// @Success      200  {array}   dto.LogLevelReturn
+// @Failure      401  {object}  dto.Problem
+// @Failure      403  {object}  dto.Problem
+// @Router       /admin/log-levels [get]
+func (h LoggingHandler) getLogLevels(c *fiber.Ctx) error {
+	levels := logger.Levels()
+
+	response := make([]dto.LogLevelReturn, 0, len(levels))
+	for _, level := range levels {
+		response = append(response, logLevelReturn(level))
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// setLogLevel godoc
+// @Summary      Set log level
+// @Description  Temporarily change the log level of a component, the configured level is restored after the duration
+// @Tags         admin
+// @Accept       json
+// @Produce      json
+// @Security Bearer
+// @Param        component path      string              true  "Component: app, http, db, email or auth"
+// @Param        body      body      dto.LogLevelUpdate  true  "Level and duration"
+// @Success      200  {object}  dto.LogLevelReturn
+// @Failure      400  {object}  dto.Problem
+// @Failure      401  {object}  dto.Problem
+// @Failure      403  {object}  dto.Problem
+// @Failure      404  {object}  dto.Problem
+// @Router       /admin/log-levels/{component} [put]
+func (h LoggingHandler) setLogLevel(c *fiber.Ctx) error {
+	component := c.Params("component")
+	if !slices.Contains(logger.Components, component) {
+		return errorz.LogComponentNotFound
+	}
+
+	var update dto.LogLevelUpdate
+	if err := c.BodyParser(&update); err != nil {
+		return errorz.InvalidBody.Wrap(err)
+	}
+
+	if errValidate := h.validator.ValidateData(update); errValidate != nil {
+		return errValidate
+	}
+
+	duration := defaultLogLevelDuration
+	if update.Duration != "" {
+		// The duration rule checks the format too, this keeps a wrong tag from applying a zero duration
+		parsed, errDuration := time.ParseDuration(update.Duration)
+		if errDuration != nil {
+			return errorz.Validation("validation failed", []errorz.FieldError{validator.NewFieldError("duration", "duration")})
+		}
+		duration = parsed
+	}
+
+	if err := logger.SetTemporaryLevel(component, update.Level, duration); err != nil {
+		return errorz.Internal(err)
+	}
+	logger.FromContext(c.Context()).Warnf("Log level of %s set to %s for %s", component, update.Level, duration)
+
+	for _, level := range logger.Levels() {
+		if level.Component == component {
+			return c.Status(fiber.StatusOK).JSON(logLevelReturn(level))
+		}
+	}
+	return errorz.LogComponentNotFound
+}
+
+// logLevelReturn is a function that converts a component log level to its response.
+func logLevelReturn(level logger.LevelStatus) dto.LogLevelReturn {
+	return dto.LogLevelReturn{
+		Component:  level.Component,
+		Level:      level.Level,
+		Configured: level.Configured,
+		RevertAt:   level.RevertAt,
+	}
+}
+
+func (h LoggingHandler) Setup(router fiber.Router, middleware fiber.Handler) {
+	router.Get("/admin/log-levels", middleware, h.getLogLevels)
+	router.Put("/admin/log-levels/:component", middleware, h.setLogLevel)
+}
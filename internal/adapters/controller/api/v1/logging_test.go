@@ -0,0 +1,50 @@
+package v1_test
+
+import (
+	"net/http"
+	"testing"
+	"webTemplate/internal/adapters/config"
+	"webTemplate/internal/domain/dto"
+)
+
+func TestLogLevelsRights(t *testing.T) {
+	testApp := newTestApp(t, config.Roles{
+		"log-keeper":  {"manageLogs"},
+		"user-keeper": {"manageUsers"},
+	})
+	_, logKeeperToken := createUser(t, testApp, "logkeeper", "log-keeper")
+	_, userKeeperToken := createUser(t, testApp, "userkeeper", "user-keeper")
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		token      string
+		body       string
+		wantStatus int
+	}{
+		{name: "list with manageLogs only", method: http.MethodGet, path: "/api/v1/admin/log-levels", token: logKeeperToken, wantStatus: http.StatusOK},
+		{name: "set with manageLogs only", method: http.MethodPut, path: "/api/v1/admin/log-levels/db", token: logKeeperToken, body: `{"level": "info", "duration": "1m"}`, wantStatus: http.StatusOK},
+		{name: "set with a malformed duration", method: http.MethodPut, path: "/api/v1/admin/log-levels/db", token: logKeeperToken, body: `{"level": "info", "duration": "soon"}`, wantStatus: http.StatusBadRequest},
+		{name: "set with a duration over 24h", method: http.MethodPut, path: "/api/v1/admin/log-levels/db", token: logKeeperToken, body: `{"level": "info", "duration": "25h"}`, wantStatus: http.StatusBadRequest},
+		{name: "set without a duration", method: http.MethodPut, path: "/api/v1/admin/log-levels/db", token: logKeeperToken, body: `{"level": "info"}`, wantStatus: http.StatusOK},
+		{name: "list without manageLogs", method: http.MethodGet, path: "/api/v1/admin/log-levels", token: userKeeperToken, wantStatus: http.StatusForbidden},
+		{name: "users without manageUsers", method: http.MethodGet, path: "/api/v1/admin/users", token: logKeeperToken, wantStatus: http.StatusForbidden},
+		{name: "users with manageUsers only", method: http.MethodGet, path: "/api/v1/admin/users", token: userKeeperToken, wantStatus: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := do(t, testApp, tt.method, tt.path, tt.token, tt.body)
+			if status != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", status, tt.wantStatus, body)
+			}
+		})
+	}
+
+	_, body := do(t, testApp, http.MethodGet, "/api/v1/admin/log-levels", logKeeperToken, "")
+	var levels []dto.LogLevelReturn
+	decode(t, body, &levels)
+	if len(levels) == 0 {
+		t.Error("no log levels returned")
+	}
+}
@@ -0,0 +1,52 @@
+package v1
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"webTemplate/cmd/app"
+	"webTemplate/internal/adapters/logger"
+	"webTemplate/internal/adapters/metrics"
+	"webTemplate/internal/domain/common/errorz"
+)
+
+type MetricsHandler struct {
+	token string
+}
+
+func NewMetricsHandler(app *app.App) *MetricsHandler {
+	if app.DB != nil {
+		if sqlDB, err := app.DB.DB(); err != nil {
+			logger.Log.Errorf("Failed to get database pool for metrics: %v", err)
+		} else if err = metrics.RegisterDB("primary", sqlDB); err != nil {
+			logger.Log.Errorf("Failed to register database metrics: %v", err)
+		}
+	}
+	for i, replica := range app.Config.Replicas {
+		if err := metrics.RegisterDB(fmt.Sprintf("replica-%d", i+1), replica); err != nil {
+			logger.Log.Errorf("Failed to register database replica metrics: %v", err)
+		}
+	}
+
+	return &MetricsHandler{
+		token: app.Config.Service.Backend.Metrics.Token.Reveal(),
+	}
+}
+
+// authorize is a middleware that requires the metrics token as a bearer token, if it is set.
+func (h MetricsHandler) authorize(c *fiber.Ctx) error {
+	if h.token == "" {
+		return c.Next()
+	}
+
+	expected := "Bearer " + h.token
+	if subtle.ConstantTimeCompare([]byte(c.Get(fiber.HeaderAuthorization)), []byte(expected)) != 1 {
+		return errorz.InvalidToken
+	}
+	return c.Next()
+}
+
+// Setup is a method that registers /metrics at the root of the app, outside of the rate limited api.
+func (h MetricsHandler) Setup(router fiber.Router) {
+	router.Get("/metrics", h.authorize, metrics.Handler())
+}
@@ -2,35 +2,59 @@ package middlewares
 
 import (
 	"context"
+	"errors"
 	"github.com/gofiber/fiber/v2"
 	"webTemplate/cmd/app"
 	"webTemplate/internal/adapters/config"
 	"webTemplate/internal/adapters/database/postgres"
+	"webTemplate/internal/adapters/logger"
 	"webTemplate/internal/domain/common/errorz"
 	"webTemplate/internal/domain/entity"
 	"webTemplate/internal/domain/service"
 	"webTemplate/internal/domain/utils/auth"
 )
 
+// UserKey is the fiber locals key of the authenticated *entity.User.
+const UserKey = "user"
+
 type UserService interface {
 	GetByID(ctx context.Context, uuid string) (*entity.User, error)
 }
 
+type TokenService interface {
+	GetByToken(ctx context.Context, token string, tokenType string) (*entity.Token, error)
+}
+
 type MiddlewareHandler struct {
-	userService UserService
+	userService  UserService
+	tokenService TokenService
+	config       *config.Config
 }
 
 // NewMiddlewareHandler is a function that returns a new instance of MiddlewareHandler.
 func NewMiddlewareHandler(app *app.App) *MiddlewareHandler {
-	userStorage := postgres.NewUserStorage(app.DB)
-	userService := service.NewUserService(userStorage)
+	userService := service.NewUserService(app.Storages.Users)
 
 	return &MiddlewareHandler{
-		userService: userService,
+		userService:  userService,
+		tokenService: service.NewTokenService(app.Storages.Tokens, app.Config.Service.Backend.JWT),
+		config:       app.Config,
 	}
 }
 
-// IsAuthenticated is a function that checks whether the user has sufficient rights to access the endpoint
+// getUser is a method that fetches the user of a token, from the primary if the user or its client IP has written recently.
+func (h MiddlewareHandler) getUser(ctx context.Context, id string) (*entity.User, error) {
+	return h.userService.GetByID(postgres.WithStickyKeys(ctx, userStickyKey(id)), id)
+}
+
+// userStickyKey is a function that returns the read-your-writes key of a user, see postgres.StickyKey.
+func userStickyKey(id string) string {
+	return "user:" + id
+}
+
+// IsAuthenticated is a function that checks whether the user has sufficient rights to access the endpoint.
+// The token must still be stored, so tokens revoked by logout are rejected.
+// The authenticated user is stored in fiber locals under UserKey.
 /*
  * tokenType string - the type of token that is required to access the endpoint
  * requiredRights ...string - the rights that the user must have
@@ -39,20 +63,27 @@ func (h MiddlewareHandler) IsAuthenticated(tokenType string, requiredRights ...s
 	return func(c *fiber.Ctx) error {
 		authHeader := c.Get("Authorization")
 
-		user, fetchErr := auth.GetUserFromJWT(authHeader, tokenType, c.Context(), h.userService.GetByID)
+		user, fetchErr := auth.GetUserFromJWT(authHeader, h.config.Service.Backend.JWT.Secret.Reveal(), tokenType, c.Context(), h.getUser)
 		if fetchErr != nil {
-			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-				"success": false,
-				"message": fetchErr.Error(),
-			})
+			return fetchErr
 		}
 
-		if !config.RoleHasRights(user.Role, requiredRights) {
-			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-				"success": false,
-				"message": errorz.Forbidden,
-			})
+		stickyCtx := postgres.WithStickyKeys(c.Context(), userStickyKey(user.ID))
+		stored, storedErr := h.tokenService.GetByToken(stickyCtx, auth.BearerToken(authHeader), tokenType)
+		if errors.Is(storedErr, errorz.TokenNotFound) || (storedErr == nil && stored.UserID != user.ID) {
+			return errorz.InvalidToken.Wrap(storedErr)
+		}
+		if storedErr != nil {
+			return storedErr
 		}
+
+		if !h.config.Live().Roles.HasRights(user.Role, requiredRights) {
+			return errorz.Forbidden
+		}
+
+		c.Locals(UserKey, user)
+		c.Locals(postgres.StickyKey, postgres.StickyKeysOf(stickyCtx))
+		c.Locals(logger.ContextKey, logger.FromContext(c.Context()).With("user_id", user.ID))
 		return c.Next()
 	}
 }
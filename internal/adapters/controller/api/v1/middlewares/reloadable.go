@@ -0,0 +1,57 @@
+package middlewares
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/cors"
+	"github.com/gofiber/fiber/v2/middleware/limiter"
+	"strings"
+	"sync/atomic"
+	"webTemplate/internal/adapters/config"
+	"webTemplate/internal/domain/common/errorz"
+)
+
+// CORS is a function that returns the CORS middleware using the reloadable "service.backend.cors" settings.
+func CORS(cfg *config.Config) fiber.Handler {
+	return reloadable(cfg, func(live *config.Reloadable) fiber.Handler {
+		return cors.New(cors.Config{
+			AllowOrigins: strings.Join(live.CORS.AllowOrigins, ","),
+		})
+	})
+}
+
+// RateLimit is a function that returns the per client IP rate limiting middleware using the reloadable
+// "service.backend.rate-limit" settings. Request counters start over when the settings change.
+func RateLimit(cfg *config.Config) fiber.Handler {
+	return reloadable(cfg, func(live *config.Reloadable) fiber.Handler {
+		if !live.RateLimit.Enabled {
+			return func(c *fiber.Ctx) error {
+				return c.Next()
+			}
+		}
+
+		return limiter.New(limiter.Config{
+			Max:        live.RateLimit.Max,
+			Expiration: live.RateLimit.Window,
+			LimitReached: func(c *fiber.Ctx) error {
+				return errorz.TooManyRequests
+			},
+		})
+	})
+}
+
+// reloadable is a function that returns a middleware delegating to the one built from the current reloadable settings.
+// The delegate is rebuilt and swapped atomically on every config reload.
+func reloadable(cfg *config.Config, build func(live *config.Reloadable) fiber.Handler) fiber.Handler {
+	var current atomic.Pointer[fiber.Handler]
+
+	handler := build(cfg.Live())
+	current.Store(&handler)
+	cfg.OnReload(func(live *config.Reloadable) {
+		handler := build(live)
+		current.Store(&handler)
+	})
+
+	return func(c *fiber.Ctx) error {
+		return (*current.Load())(c)
+	}
+}
@@ -0,0 +1,86 @@
+package middlewares
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"sync/atomic"
+	"time"
+	"webTemplate/internal/adapters/database/postgres"
+	"webTemplate/internal/adapters/logger"
+	"webTemplate/internal/domain/common/requestid"
+)
+
+// RequestID is a function that returns a middleware assigning an id to every request.
+// A valid X-Request-ID header of the request is kept, otherwise a new id is generated.
+// The id is returned in the X-Request-ID response header and stored in fiber locals under requestid.ContextKey.
+func RequestID() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Get(requestid.Header)
+		if !requestid.Valid(id) {
+			id = requestid.New()
+		}
+
+		c.Set(requestid.Header, id)
+		c.Locals(requestid.ContextKey, id)
+		return c.Next()
+	}
+}
+
+// RequestLogger is a function that returns a middleware placing a request-scoped logger in fiber locals under logger.ContextKey.
+// Its entries carry the request id, method, path, route and latency, IsAuthenticated adds the user id.
+// RequestID must run first.
+func RequestLogger() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		// The route is known only after routing, it is frozen when the request completes as c is reused afterwards
+		var completedRoute atomic.Pointer[string]
+		route := func() string {
+			if path := completedRoute.Load(); path != nil {
+				return *path
+			}
+			return c.Route().Path
+		}
+
+		c.Locals(logger.ContextKey, logger.ForRequest(
+			time.Now(),
+			route,
+			"request_id", requestid.FromContext(c.Context()),
+			"method", c.Method(),
+			"path", c.Path(),
+		))
+
+		err := c.Next()
+
+		path := c.Route().Path
+		completedRoute.Store(&path)
+		return err
+	}
+}
+
+// AccessLog is a function that returns a middleware logging every completed request with its status, size and client.
+// Errors are passed to the app error handler here, so the logged status is the one sent to the client.
+// RequestLogger must run first, the entry gets the request id, route, latency and user id from it.
+func AccessLog() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if err := c.Next(); err != nil {
+			if errHandler := c.App().ErrorHandler(c, err); errHandler != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
+		logger.FromContext(c.Context()).Named(logger.ComponentHTTP).Infow("request completed",
+			"status", c.Response().StatusCode(),
+			"bytes", len(c.Response().Body()),
+			"ip", c.IP(),
+		)
+		return nil
+	}
+}
+
+// ReadYourWrites is a function that returns a middleware keying database reads and writes of a request by the client IP,
+// so reads after its writes go to the primary instead of a lagging replica. IsAuthenticated adds the user id, so the
+// first requests of a user after register or login from the same IP are sticky too.
+func ReadYourWrites() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		c.Locals(postgres.StickyKey, []string{"ip:" + c.IP()})
+		return c.Next()
+	}
+}
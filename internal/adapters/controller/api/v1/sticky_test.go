@@ -0,0 +1,34 @@
+package v1_test
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"webTemplate/internal/adapters/controller/api/v1/middlewares"
+	"webTemplate/internal/adapters/database/postgres"
+	"webTemplate/internal/domain/entity"
+	"webTemplate/internal/domain/utils/auth"
+)
+
+func TestAuthenticatedRequestKeepsIPStickyKey(t *testing.T) {
+	testApp := newTestApp(t, nil)
+	user, token := createUser(t, testApp, "sticky", entity.RoleUser)
+
+	middlewareHandler := middlewares.NewMiddlewareHandler(testApp)
+	testApp.Fiber.Get("/sticky-keys", middlewareHandler.IsAuthenticated(auth.TokenTypeAccess), func(c *fiber.Ctx) error {
+		return c.JSON(postgres.StickyKeysOf(c.Context()))
+	})
+
+	status, body := do(t, testApp, http.MethodGet, "/sticky-keys", token, "")
+	if status != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", status, http.StatusOK, body)
+	}
+	var keys []string
+	decode(t, body, &keys)
+	// Writes of register and login are keyed by IP only, the user's first requests must still read them
+	if len(keys) != 2 || !strings.HasPrefix(keys[0], "ip:") || !slices.Contains(keys, "user:"+user.ID) {
+		t.Errorf("sticky keys = %v, want the IP and user:%s", keys, user.ID)
+	}
+}
@@ -2,53 +2,56 @@ package v1
 
 import (
 	"context"
-	"fmt"
 	"github.com/gofiber/fiber/v2"
-	"github.com/spf13/viper"
-	"time"
+	"net/mail"
 	"webTemplate/cmd/app"
+	"webTemplate/internal/adapters/controller/api/v1/middlewares"
 	"webTemplate/internal/adapters/controller/api/validator"
-	"webTemplate/internal/adapters/database/postgres"
-	"webTemplate/internal/adapters/logger"
+	"webTemplate/internal/domain/common/errorz"
 	"webTemplate/internal/domain/dto"
 	"webTemplate/internal/domain/entity"
 	"webTemplate/internal/domain/service"
-	"webTemplate/internal/domain/utils/auth"
+	authUsecase "webTemplate/internal/domain/usecase/auth"
+	"webTemplate/internal/domain/utils/username"
 )
 
-type UserService interface {
-	Create(ctx context.Context, registerReq dto.UserRegister, code string) (*entity.User, error)
-	GetByID(ctx context.Context, uuid string) (*entity.User, error)
-	Update(ctx context.Context, user *entity.User) (*entity.User, error)
-	GetByEmail(ctx context.Context, email string) (*entity.User, error)
-}
-
-type TokenService interface {
-	GenerateAuthTokens(c context.Context, userID string) (*dto.AuthTokens, error)
-	GenerateToken(ctx context.Context, userID string, expires time.Time, tokenType string) (*entity.Token, error)
+type AuthUsecase interface {
+	Register(ctx context.Context, registerReq dto.UserRegister) (*dto.UserRegisterResponse, error)
+	Login(ctx context.Context, loginReq dto.UserLogin) (*dto.UserRegisterResponse, error)
+	Refresh(ctx context.Context, refreshToken string) (*dto.Token, error)
+	Verify(ctx context.Context, user *entity.User, code string) error
+	ChangePassword(ctx context.Context, user *entity.User, changeReq dto.UserChangePassword) error
+	Logout(ctx context.Context, user *entity.User) error
 }
 
-type EmailService interface {
-	Send(ctx context.Context, email string, text string, subject string) error
-	Check(ctx context.Context, email string) (bool, error)
+type UserService interface {
+	EmailAvailable(ctx context.Context, email string) (bool, error)
+	UsernameAvailable(ctx context.Context, username string) (bool, error)
 }
 
 type UserHandler struct {
-	userService  UserService
-	tokenService TokenService
-	emailService EmailService
-	validator    *validator.Validator
+	authUsecase   AuthUsecase
+	userService   UserService
+	validator     *validator.Validator
+	usernameRules *username.Rules
 }
 
 func NewUserHandler(app *app.App) *UserHandler {
-	userStorage := postgres.NewUserStorage(app.DB)
-	tokenStorage := postgres.NewTokenStorage(app.DB)
+	userService := service.NewUserService(app.Storages.Users)
 
 	return &UserHandler{
-		userService:  service.NewUserService(userStorage),
-		tokenService: service.NewTokenService(tokenStorage),
-		emailService: service.NewEmailService(app.Maileroo),
-		validator:    app.Validator,
+		authUsecase: authUsecase.New(
+			app.Storages.Transactor,
+			userService,
+			service.NewTokenService(app.Storages.Tokens, app.Config.Service.Backend.JWT),
+			service.NewOutboxService(app.Storages.Outbox),
+			service.NewEmailService(app.Maileroo, app.Storages.Users),
+			app.PasswordPolicy,
+			app.PasswordHasher,
+		),
+		userService:   userService,
+		validator:     app.Validator,
+		usernameRules: app.UsernameRules,
 	}
 }
 
@@ -60,70 +63,24 @@ func NewUserHandler(app *app.App) *UserHandler {
 // @Produce      json
 // @Param        body body  dto.UserRegister true  "User registration body object"
 // @Success      201  {object}  dto.UserRegisterResponse
-// @Failure      400  {object}  dto.HTTPError
-// @Failure      500  {object}  dto.HTTPError
+// @Failure      400  {object}  dto.Problem
+// @Failure      409  {object}  dto.Problem
+// @Failure      500  {object}  dto.Problem
 // @Router       /user/register [post]
 func (h UserHandler) register(c *fiber.Ctx) error {
 	var userDTO dto.UserRegister
 
 	if err := c.BodyParser(&userDTO); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.HTTPError{
-			Code:    fiber.StatusBadRequest,
-			Message: err.Error(),
-		})
+		return errorz.InvalidBody.Wrap(err)
 	}
 
 	if errValidate := h.validator.ValidateData(userDTO); errValidate != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.HTTPError{
-			Code:    fiber.StatusBadRequest,
-			Message: errValidate.Error(),
-		})
+		return errValidate
 	}
 
-	mailValid, mvErr := h.emailService.Check(c.Context(), userDTO.Email)
-	if mvErr != nil || !mailValid {
-		logger.Log.Errorf("invalid email: %s", userDTO.Email)
-		return c.Status(fiber.StatusBadRequest).JSON(dto.HTTPError{
-			Code:    fiber.StatusBadRequest,
-			Message: "invalid email",
-		})
-	}
-
-	code := auth.GenerateCode()
-	msErr := h.emailService.Send(c.Context(), userDTO.Email, fmt.Sprintf("Your code is: <b>%s</b>", code), "Verification Code")
-	if msErr != nil {
-		logger.Log.Errorf("email sending error: %s", msErr.Error())
-		return c.Status(fiber.StatusInternalServerError).JSON(dto.HTTPError{
-			Code:    fiber.StatusInternalServerError,
-			Message: msErr.Error(),
-		})
-	}
-
-	user, errCreate := h.userService.Create(c.Context(), userDTO, code)
-	if errCreate != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(dto.HTTPError{
-			Code:    fiber.StatusInternalServerError,
-			Message: errCreate.Error(),
-		})
-	}
-
-	tokens, tokensErr := h.tokenService.GenerateAuthTokens(c.Context(), user.ID)
-	if tokensErr != nil || tokens == nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(dto.HTTPError{
-			Code:    fiber.StatusInternalServerError,
-			Message: "failed to generate auth tokens",
-		})
-	}
-
-	response := dto.UserRegisterResponse{
-		User: dto.UserReturn{
-			ID:            user.ID,
-			Email:         user.Email,
-			VerifiedEmail: user.VerifiedEmail,
-			Username:      user.Username,
-			Role:          user.Role,
-		},
-		Tokens: *tokens,
+	response, errRegister := h.authUsecase.Register(c.Context(), userDTO)
+	if errRegister != nil {
+		return errRegister
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(response)
@@ -137,61 +94,24 @@ func (h UserHandler) register(c *fiber.Ctx) error {
 // @Produce      json
 // @Param        body body  dto.UserLogin true  "User login body object"
 // @Success      200  {object}  dto.UserRegisterResponse
-// @Failure      400  {object}  dto.HTTPError
-// @Failure      403  {object}  dto.HTTPError
-// @Failure      404  {object}  dto.HTTPError
-// @Failure      500  {object}  dto.HTTPError
+// @Failure      400  {object}  dto.Problem
+// @Failure      401  {object}  dto.Problem
+// @Failure      500  {object}  dto.Problem
 // @Router       /user/login [post]
 func (h UserHandler) login(c *fiber.Ctx) error {
 	var userDTO dto.UserLogin
 
 	if err := c.BodyParser(&userDTO); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.HTTPError{
-			Code:    fiber.StatusBadRequest,
-			Message: err.Error(),
-		})
+		return errorz.InvalidBody.Wrap(err)
 	}
 
 	if errValidate := h.validator.ValidateData(userDTO); errValidate != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.HTTPError{
-			Code:    fiber.StatusBadRequest,
-			Message: errValidate.Error(),
-		})
-	}
-
-	user, errFetch := h.userService.GetByEmail(c.Context(), userDTO.Email)
-	if errFetch != nil {
-		return c.Status(fiber.StatusNotFound).JSON(dto.HTTPError{
-			Code:    fiber.StatusNotFound,
-			Message: "not found",
-		})
-	}
-
-	passErr := user.ComparePassword(userDTO.Password)
-	if passErr != nil {
-		return c.Status(fiber.StatusForbidden).JSON(dto.HTTPError{
-			Code:    fiber.StatusForbidden,
-			Message: "invalid password",
-		})
-	}
-
-	tokens, tokensErr := h.tokenService.GenerateAuthTokens(c.Context(), user.ID)
-	if tokensErr != nil || tokens == nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(dto.HTTPError{
-			Code:    fiber.StatusInternalServerError,
-			Message: "failed to generate auth tokens",
-		})
+		return errValidate
 	}
 
-	response := dto.UserRegisterResponse{
-		User: dto.UserReturn{
-			ID:            user.ID,
-			Email:         user.Email,
-			VerifiedEmail: user.VerifiedEmail,
-			Username:      user.Username,
-			Role:          user.Role,
-		},
-		Tokens: *tokens,
+	response, errLogin := h.authUsecase.Login(c.Context(), userDTO)
+	if errLogin != nil {
+		return errLogin
 	}
 
 	return c.Status(fiber.StatusOK).JSON(response)
@@ -203,55 +123,26 @@ func (h UserHandler) login(c *fiber.Ctx) error {
 // @Tags         user
 // @Accept       json
 // @Produce      json
-// @Param        body body  dto.Token true  "Access token object"
+// @Param        body body  dto.Token true  "Refresh token object"
 // @Success      200  {object}  dto.Token
-// @Failure      400  {object}  dto.HTTPError
-// @Failure      403  {object}  dto.HTTPError
-// @Failure      500  {object}  dto.HTTPError
+// @Failure      400  {object}  dto.Problem
+// @Failure      401  {object}  dto.Problem
+// @Failure      500  {object}  dto.Problem
 // @Router       /user/refresh [post]
 func (h UserHandler) refreshToken(c *fiber.Ctx) error {
-	var accessTokenDTO dto.Token
+	var refreshTokenDTO dto.Token
 
-	if err := c.BodyParser(&accessTokenDTO); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.HTTPError{
-			Code:    fiber.StatusBadRequest,
-			Message: err.Error(),
-		})
+	if err := c.BodyParser(&refreshTokenDTO); err != nil {
+		return errorz.InvalidBody.Wrap(err)
 	}
 
-	if errValidate := h.validator.ValidateData(accessTokenDTO); errValidate != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.HTTPError{
-			Code:    fiber.StatusBadRequest,
-			Message: errValidate.Error(),
-		})
+	if errValidate := h.validator.ValidateData(refreshTokenDTO); errValidate != nil {
+		return errValidate
 	}
 
-	userID, errToken := auth.VerifyToken(accessTokenDTO.Token, viper.GetString("service.backend.jwt.secret"), auth.TokenTypeAccess)
-
-	if errToken != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(dto.HTTPError{
-			Code:    fiber.StatusUnauthorized,
-			Message: errToken.Error(),
-		})
-	}
-
-	expTime := time.Now().UTC().Add(time.Minute * time.Duration(viper.GetInt("service.backend.jwt.access-token-expiration")))
-
-	newAccess, errNewAccess := h.tokenService.GenerateToken(c.Context(),
-		userID,
-		expTime,
-		auth.TokenTypeAccess)
-
-	if errNewAccess != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(dto.HTTPError{
-			Code:    fiber.StatusInternalServerError,
-			Message: errNewAccess.Error(),
-		})
-	}
-
-	response := dto.Token{
-		Token:   newAccess.Token,
-		Expires: expTime,
+	response, errRefresh := h.authUsecase.Refresh(c.Context(), refreshTokenDTO.Token)
+	if errRefresh != nil {
+		return errRefresh
 	}
 
 	return c.Status(fiber.StatusOK).JSON(response)
@@ -266,63 +157,168 @@ func (h UserHandler) refreshToken(c *fiber.Ctx) error {
 // @Security Bearer
 // @Param        body body  dto.UserCode true  "User's email code"
 // @Success      200  {object}  dto.HTTPStatus
-// @Failure      400  {object}  dto.HTTPError
-// @Failure      401  {object}  dto.HTTPError
-// @Failure      403  {object}  dto.HTTPError
-// @Failure      500  {object}  dto.HTTPError
+// @Failure      400  {object}  dto.Problem
+// @Failure      401  {object}  dto.Problem
+// @Failure      403  {object}  dto.Problem
+// @Failure      409  {object}  dto.Problem
+// @Failure      500  {object}  dto.Problem
 // @Router       /user/verify [post]
 func (h UserHandler) verify(c *fiber.Ctx) error {
 	var userCode dto.UserCode
 
 	if err := c.BodyParser(&userCode); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.HTTPError{
-			Code:    fiber.StatusBadRequest,
-			Message: err.Error(),
-		})
+		return errorz.InvalidBody.Wrap(err)
 	}
 
 	if errValidate := h.validator.ValidateData(userCode); errValidate != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.HTTPError{
-			Code:    fiber.StatusBadRequest,
-			Message: errValidate.Error(),
-		})
+		return errValidate
 	}
 
-	user, authErr := auth.GetUserFromJWT(c.Get("Authorization"), auth.TokenTypeAccess, c.Context(), h.userService.GetByID)
+	user := c.Locals(middlewares.UserKey).(*entity.User)
+	if errVerify := h.authUsecase.Verify(c.Context(), user, userCode.Code); errVerify != nil {
+		return errVerify
+	}
 
-	if authErr != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(dto.HTTPError{
-			Code:    fiber.StatusUnauthorized,
-			Message: authErr.Error(),
-		})
+	return c.Status(fiber.StatusOK).JSON(dto.HTTPStatus{
+		Code:    fiber.StatusOK,
+		Message: "email verified",
+	})
+}
+
+// changePassword godoc
+// @Summary      Change user password
+// @Description  Change the password of the authenticated user, the new password must meet the password policy
+// @Tags         user
+// @Accept       json
+// @Produce      json
+// @Security Bearer
+// @Param        body body  dto.UserChangePassword true  "Current and new passwords"
+// @Success      200  {object}  dto.HTTPStatus
+// @Failure      400  {object}  dto.Problem
+// @Failure      401  {object}  dto.Problem
+// @Failure      500  {object}  dto.Problem
+// @Router       /user/password [put]
+func (h UserHandler) changePassword(c *fiber.Ctx) error {
+	var changeDTO dto.UserChangePassword
+
+	if err := c.BodyParser(&changeDTO); err != nil {
+		return errorz.InvalidBody.Wrap(err)
 	}
 
-	if user.VerificationCode == "NULL" {
-		return c.Status(fiber.StatusBadRequest).JSON(dto.HTTPError{
-			Code:    fiber.StatusBadRequest,
-			Message: "already verified",
-		})
+	if errValidate := h.validator.ValidateData(changeDTO); errValidate != nil {
+		return errValidate
 	}
 
-	if user.VerificationCode != userCode.Code {
-		return c.Status(fiber.StatusForbidden).JSON(dto.HTTPError{
-			Code:    fiber.StatusForbidden,
-			Message: "invalid code",
-		})
+	user := c.Locals(middlewares.UserKey).(*entity.User)
+	if errChange := h.authUsecase.ChangePassword(c.Context(), user, changeDTO); errChange != nil {
+		return errChange
 	}
 
-	user.VerificationCode = "NULL"
-	_, updateErr := h.userService.Update(c.Context(), user)
-	if updateErr != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(dto.HTTPError{
-			Code:    fiber.StatusInternalServerError,
-			Message: updateErr.Error(),
-		})
+	return c.Status(fiber.StatusOK).JSON(dto.HTTPStatus{
+		Code:    fiber.StatusOK,
+		Message: "password changed",
+	})
+}
+
+// logout godoc
+// @Summary      Logout from user account
+// @Description  Revoke all refresh and access tokens of the user, requests with them are rejected from then on
+// @Tags         user
+// @Produce      json
+// @Security Bearer
+// @Success      200  {object}  dto.HTTPStatus
+// @Failure      401  {object}  dto.Problem
+// @Failure      500  {object}  dto.Problem
+// @Router       /user/logout [post]
+func (h UserHandler) logout(c *fiber.Ctx) error {
+	user := c.Locals(middlewares.UserKey).(*entity.User)
+	if errLogout := h.authUsecase.Logout(c.Context(), user); errLogout != nil {
+		return errLogout
 	}
 
-	response := dto.HTTPStatus{
-		Code:    200,
-		Message: "email verified",
+	return c.Status(fiber.StatusOK).JSON(dto.HTTPStatus{
+		Code:    fiber.StatusOK,
+		Message: "logged out",
+	})
+}
+
+// emailAvailability godoc
+// @Summary      Check email availability
+// @Description  Check whether an email can be used for registration, emails are compared case-insensitively
+// @Tags         user
+// @Produce      json
+// @Param        value query     string  true  "Email to check"
+// @Success      200  {object}  dto.Availability
+// @Failure      400  {object}  dto.Problem
+// @Failure      500  {object}  dto.Problem
+// @Router       /user/available/email [get]
+func (h UserHandler) emailAvailability(c *fiber.Ctx) error {
+	var query dto.AvailabilityQuery
+
+	if err := c.QueryParser(&query); err != nil {
+		return errorz.InvalidBody.Wrap(err)
+	}
+
+	if errValidate := h.validator.ValidateData(query); errValidate != nil {
+		return errValidate
+	}
+
+	response := dto.Availability{Value: query.Value}
+	if _, err := mail.ParseAddress(query.Value); err != nil {
+		response.Reason = "invalid"
+		return c.Status(fiber.StatusOK).JSON(response)
+	}
+
+	available, err := h.userService.EmailAvailable(c.Context(), query.Value)
+	if err != nil {
+		return err
+	}
+	response.Available = available
+	if !available {
+		response.Reason = "taken"
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// usernameAvailability godoc
+// @Summary      Check username availability
+// @Description  Check whether a username can be used for registration, usernames are compared case-insensitively after NFKC normalization
+// @Tags         user
+// @Produce      json
+// @Param        value query     string  true  "Username to check"
+// @Success      200  {object}  dto.Availability
+// @Failure      400  {object}  dto.Problem
+// @Failure      500  {object}  dto.Problem
+// @Router       /user/available/username [get]
+func (h UserHandler) usernameAvailability(c *fiber.Ctx) error {
+	var query dto.AvailabilityQuery
+
+	if err := c.QueryParser(&query); err != nil {
+		return errorz.InvalidBody.Wrap(err)
+	}
+
+	if errValidate := h.validator.ValidateData(query); errValidate != nil {
+		return errValidate
+	}
+
+	response := dto.Availability{Value: query.Value}
+	if h.usernameRules.IsReserved(query.Value) {
+		response.Reason = "reserved"
+		return c.Status(fiber.StatusOK).JSON(response)
+	}
+	if violations := h.usernameRules.Check(query.Value); len(violations) > 0 {
+		response.Reason = "invalid"
+		return c.Status(fiber.StatusOK).JSON(response)
+	}
+
+	available, err := h.userService.UsernameAvailable(c.Context(), query.Value)
+	if err != nil {
+		return err
+	}
+	response.Available = available
+	if !available {
+		response.Reason = "taken"
 	}
 
 	return c.Status(fiber.StatusOK).JSON(response)
@@ -333,5 +329,9 @@ func (h UserHandler) Setup(router fiber.Router, middleware fiber.Handler) {
 	userGroup.Post("/register", h.register)
 	userGroup.Post("/login", h.login)
 	userGroup.Post("/refresh", h.refreshToken)
-	userGroup.Post("/verify", h.verify, middleware)
+	userGroup.Get("/available/email", h.emailAvailability)
+	userGroup.Get("/available/username", h.usernameAvailability)
+	userGroup.Post("/verify", middleware, h.verify)
+	userGroup.Put("/password", middleware, h.changePassword)
+	userGroup.Post("/logout", middleware, h.logout)
 }
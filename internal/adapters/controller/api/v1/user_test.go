@@ -0,0 +1,30 @@
+package v1_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+	"webTemplate/internal/domain/entity"
+	"webTemplate/internal/domain/utils/auth"
+)
+
+func TestLogoutRevokesAccessToken(t *testing.T) {
+	testApp := newTestApp(t, nil)
+	user, token := createUser(t, testApp, "leaving", entity.RoleUser)
+
+	if status, body := do(t, testApp, http.MethodPost, "/api/v1/user/logout", token, ""); status != http.StatusOK {
+		t.Fatalf("logout: status = %d, want %d: %s", status, http.StatusOK, body)
+	}
+	if status, body := do(t, testApp, http.MethodPost, "/api/v1/user/logout", token, ""); status != http.StatusUnauthorized {
+		t.Errorf("request after logout: status = %d, want %d: %s", status, http.StatusUnauthorized, body)
+	}
+
+	// A correctly signed token that was never stored is rejected too
+	unstored, err := auth.GenerateToken(user.ID, time.Now().Add(time.Hour), auth.TokenTypeAccess, testApp.Config.Service.Backend.JWT.Secret.Reveal())
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+	if status, body := do(t, testApp, http.MethodPost, "/api/v1/user/logout", unstored, ""); status != http.StatusUnauthorized {
+		t.Errorf("unstored token: status = %d, want %d: %s", status, http.StatusUnauthorized, body)
+	}
+}
@@ -0,0 +1,85 @@
+package v1
+
+import (
+	"context"
+	"github.com/gofiber/fiber/v2"
+	"time"
+	"webTemplate/cmd/app"
+	"webTemplate/internal/adapters/controller/api/validator"
+	"webTemplate/internal/domain/common/errorz"
+	"webTemplate/internal/domain/dto"
+	"webTemplate/internal/domain/service"
+	"webTemplate/internal/domain/utils/webhook"
+)
+
+// signatureTolerance is the maximum age of a signed webhook request.
+const signatureTolerance = 5 * time.Minute
+
+type EmailEventService interface {
+	HandleAll(ctx context.Context, events []dto.EmailWebhookEvent) error
+}
+
+type WebhookHandler struct {
+	emailEventService EmailEventService
+	validator         *validator.Validator
+	secret            string
+}
+
+func NewWebhookHandler(app *app.App) *WebhookHandler {
+	return &WebhookHandler{
+		emailEventService: service.NewEmailEventService(app.Storages.Transactor, app.Storages.EmailEvents, app.Storages.Users),
+		validator:         app.Validator,
+		secret:            app.Maileroo.WebhookSecret.Reveal(),
+	}
+}
+
+// emailEvents godoc
+// @Summary      Receive email delivery events
+// @Description  Receive bounce, complaint and delivered events from the email provider. The body must be signed with HMAC-SHA256 of "<X-Webhook-Timestamp>.<body>" using the shared webhook secret. Events of a batch are stored all together or none of them, so a failed batch can be retried
+// @Tags         webhook
+// @Accept       json
+// @Produce      json
+// @Param        X-Webhook-Timestamp header string true "Unix timestamp the request was signed at"
+// @Param        X-Webhook-Signature header string true "Request signature in sha256=<hex> format"
+// @Param        body body  dto.EmailWebhookPayload true  "Delivery events"
+// @Success      200  {object}  dto.HTTPStatus
+// @Failure      400  {object}  dto.Problem
+// @Failure      401  {object}  dto.Problem
+// @Failure      500  {object}  dto.Problem
+// @Failure      503  {object}  dto.Problem
+// @Router       /webhooks/email [post]
+func (h WebhookHandler) emailEvents(c *fiber.Ctx) error {
+	if err := webhook.VerifySignature(
+		h.secret,
+		c.Get("X-Webhook-Timestamp"),
+		c.Body(),
+		c.Get("X-Webhook-Signature"),
+		signatureTolerance,
+	); err != nil {
+		return err
+	}
+
+	var payload dto.EmailWebhookPayload
+
+	if err := c.BodyParser(&payload); err != nil {
+		return errorz.InvalidBody.Wrap(err)
+	}
+
+	if errValidate := h.validator.ValidateData(payload); errValidate != nil {
+		return errValidate
+	}
+
+	if err := h.emailEventService.HandleAll(c.Context(), payload.Events); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.HTTPStatus{
+		Code:    fiber.StatusOK,
+		Message: "events accepted",
+	})
+}
+
+func (h WebhookHandler) Setup(router fiber.Router) {
+	webhookGroup := router.Group("/webhooks")
+	webhookGroup.Post("/email", h.emailEvents)
+}
@@ -0,0 +1,50 @@
+package v1_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+	"webTemplate/internal/adapters/config"
+	"webTemplate/internal/domain/dto"
+	"webTemplate/internal/domain/entity"
+	"webTemplate/internal/domain/utils/webhook"
+)
+
+func TestEmailWebhookWithMemoryStorage(t *testing.T) {
+	const secret = "test-webhook-secret"
+	t.Setenv("APP_SERVICE_MAILEROO_WEBHOOK_SECRET", secret)
+	testApp := newTestApp(t, config.Roles{"user-keeper": {"manageUsers"}})
+	user, _ := createUser(t, testApp, "bounced", entity.RoleUser)
+	_, adminToken := createUser(t, testApp, "userkeeper", "user-keeper")
+
+	body := `{"events": [{"type": "bounce", "email": "bounced@example.com", "message_id": "m1", "reason": "550"}]}`
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	request := httptest.NewRequest(http.MethodPost, "/api/v1/webhooks/email", strings.NewReader(body))
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("X-Webhook-Timestamp", timestamp)
+	request.Header.Set("X-Webhook-Signature", webhook.Sign(secret, timestamp, []byte(body)))
+
+	response, err := testApp.Fiber.Test(request, -1)
+	if err != nil {
+		t.Fatalf("POST /webhooks/email: %v", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		responseBody, _ := io.ReadAll(response.Body)
+		t.Fatalf("status = %d, want %d: %s", response.StatusCode, http.StatusOK, responseBody)
+	}
+
+	status, responseBody := do(t, testApp, http.MethodGet, "/api/v1/admin/users/"+user.ID, adminToken, "")
+	if status != http.StatusOK {
+		t.Fatalf("GET user: status = %d: %s", status, responseBody)
+	}
+	var adminUser dto.AdminUserReturn
+	decode(t, responseBody, &adminUser)
+	if adminUser.EmailDeliveryState != entity.EmailDeliveryBounced || len(adminUser.EmailEvents) != 1 {
+		t.Errorf("user = %+v, want a bounced email and one event", adminUser)
+	}
+}
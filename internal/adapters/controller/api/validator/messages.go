@@ -0,0 +1,120 @@
+package validator
+
+import (
+	"strings"
+	"sync"
+)
+
+// DefaultLocale is the locale of messages returned by ValidateData.
+const DefaultLocale = "en"
+
+// fallbackRule is the message key used for rules without their own message.
+const fallbackRule = "*"
+
+var (
+	messagesMu sync.RWMutex
+	// messages is a map of locales to rule message templates.
+	// "{field}" is replaced with the field name, "{name}" with the rule param of the same name.
+	messages = map[string]map[string]string{
+		"en": {
+			fallbackRule:        "{field} is invalid",
+			"required":          "{field} is required",
+			"email":             "{field} must be a valid email address",
+			"min":               "{field} must be at least {value} long",
+			"max":               "{field} must be at most {value} long",
+			"len":               "{field} must be exactly {value} long",
+			"oneof":             "{field} must be one of: {value}",
+			"username":          "{field} does not meet the username rules",
+			"username_length":   "{field} must be {min} to {max} characters long",
+			"username_chars":    "{field} may only contain {classes} and \"{symbols}\"",
+			"username_reserved": "{field} is reserved",
+			"code":              "{field} must be {length} characters of upper case letters and digits",
+			"password":          "{field} does not meet the password policy",
+			"password_length":   "{field} must be {min} to {max} characters long",
+			"password_upper":    "{field} must contain an upper case letter",
+			"password_lower":    "{field} must contain a lower case letter",
+			"password_digit":    "{field} must contain a digit",
+			"password_symbol":   "{field} must contain a symbol",
+			"password_personal": "{field} must not contain your email or username",
+			"password_weak":     "{field} is too easy to guess",
+			"password_breached": "{field} has appeared in a data breach, choose another one",
+			"header":            "{field} must be {min} to {max} characters long",
+			"body":              "{field} must be {min} to {max} characters long",
+			"duration":          "{field} must be a duration from {min} to {max}, e.g. \"15m\"",
+			"integer":           "{field} must be an integer",
+		},
+		"ru": {
+			fallbackRule:        "{field}: некорректное значение",
+			"required":          "{field}: обязательное поле",
+			"email":             "{field}: некорректный адрес электронной почты",
+			"min":               "{field}: минимальная длина {value}",
+			"max":               "{field}: максимальная длина {value}",
+			"len":               "{field}: длина должна быть {value}",
+			"oneof":             "{field}: допустимые значения: {value}",
+			"username":          "{field}: имя пользователя не соответствует требованиям",
+			"username_length":   "{field}: длина от {min} до {max} символов",
+			"username_chars":    "{field}: допустимы только {classes} и \"{symbols}\"",
+			"username_reserved": "{field}: это имя зарезервировано",
+			"code":              "{field}: {length} символов из заглавных букв и цифр",
+			"password":          "{field}: пароль не соответствует требованиям",
+			"password_length":   "{field}: длина от {min} до {max} символов",
+			"password_upper":    "{field}: нужна заглавная буква",
+			"password_lower":    "{field}: нужна строчная буква",
+			"password_digit":    "{field}: нужна цифра",
+			"password_symbol":   "{field}: нужен специальный символ",
+			"password_personal": "{field}: пароль не должен содержать email или имя пользователя",
+			"password_weak":     "{field}: пароль слишком простой",
+			"password_breached": "{field}: пароль найден в утечках, выберите другой",
+			"header":            "{field}: длина от {min} до {max} символов",
+			"body":              "{field}: длина от {min} до {max} символов",
+			"duration":          "{field}: длительность от {min} до {max}, например \"15m\"",
+			"integer":           "{field}: должно быть целым числом",
+		},
+	}
+)
+
+// RegisterMessage is a function that adds or replaces the message template of a rule for a locale.
+func RegisterMessage(locale, rule, template string) {
+	messagesMu.Lock()
+	defer messagesMu.Unlock()
+
+	if _, ok := messages[locale]; !ok {
+		messages[locale] = make(map[string]string)
+	}
+	messages[locale][rule] = template
+}
+
+// Locales is a function that returns all locales with registered messages, the default locale goes first.
+func Locales() []string {
+	messagesMu.RLock()
+	defer messagesMu.RUnlock()
+
+	locales := []string{DefaultLocale}
+	for locale := range messages {
+		if locale != DefaultLocale {
+			locales = append(locales, locale)
+		}
+	}
+	return locales
+}
+
+// Message is a function that renders the message of a failed rule in the given locale.
+// Missing locales fall back to DefaultLocale, missing rules to a generic message.
+func Message(locale, rule, field string, params map[string]string) string {
+	messagesMu.RLock()
+	defer messagesMu.RUnlock()
+
+	template, ok := messages[locale][rule]
+	if !ok {
+		template, ok = messages[DefaultLocale][rule]
+	}
+	if !ok {
+		template = messages[DefaultLocale][fallbackRule]
+	}
+
+	replacements := []string{"{field}", field}
+	for name, value := range params {
+		replacements = append(replacements, "{"+name+"}", value)
+	}
+	return strings.NewReplacer(replacements...).Replace(template)
+}
@@ -1,37 +1,53 @@
 package validator
 
 import (
-	"fmt"
+	"errors"
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
+	"reflect"
 	"strconv"
 	"strings"
+	"time"
 	"unicode"
 	"webTemplate/internal/adapters/logger"
+	"webTemplate/internal/domain/common/errorz"
+	"webTemplate/internal/domain/utils/password"
+	"webTemplate/internal/domain/utils/username"
 )
 
+// MaxLimit is the largest page size, larger limits are clamped to it.
+const MaxLimit = 100
+
 type Validator struct {
 	validator *validator.Validate
+	// policies is a map of rules to checks reporting every failed requirement, e.g. "password" and "username"
+	policies map[string]func(value string) []errorz.FieldError
 }
 
-type GlobalErrorHandlerResp struct {
-	Success bool   `json:"success"`
-	Message string `json:"message"`
-}
-
-type ErrorResponse struct {
-	Error       bool
-	FailedField string
-	Tag         string
-	Value       interface{}
+// ruleParams is a map of custom rules to the params used in their messages.
+var ruleParams = map[string]map[string]string{
+	"code":     {"length": "6"},
+	"header":   {"min": "5", "max": "150"},
+	"body":     {"min": "5", "max": "1500"},
+	"duration": {"min": "1s", "max": "24h"},
 }
 
-func New() *Validator {
+// New is a function that creates a validator with custom rules.
+// The "password" and "username" rules check values against the password policy and the username rules.
+func New(passwordPolicy *password.Policy, usernameRules *username.Rules) *Validator {
 	logger.Log.Info("Initializing validator...")
 	newValidator := validator.New()
 
-	_ = newValidator.RegisterValidation("username", func(fl validator.FieldLevel) bool {
-		return len(fl.Field().String()) >= 4 && len(fl.Field().String()) <= 20
+	// Report fields by their json names
+	newValidator.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
 	})
 
 	_ = newValidator.RegisterValidation("code", func(fl validator.FieldLevel) bool {
@@ -44,16 +60,6 @@ func New() *Validator {
 		return hasLength && (hasUppercase || hasDigit)
 	})
 
-	_ = newValidator.RegisterValidation("password", func(fl validator.FieldLevel) bool {
-		password := fl.Field().String()
-		hasMinLength := len(password) >= 8
-		hasUppercase := strings.ToLower(password) != password
-		hasLowercase := strings.ToUpper(password) != password
-		hasDigit := strings.IndexFunc(password, func(c rune) bool { return unicode.IsDigit(c) }) != -1
-
-		return hasMinLength && hasUppercase && hasLowercase && hasDigit
-	})
-
 	_ = newValidator.RegisterValidation("header", func(fl validator.FieldLevel) bool {
 		return len(fl.Field().String()) >= 5 && len(fl.Field().String()) <= 150
 	})
@@ -62,56 +68,144 @@ func New() *Validator {
 		return len(fl.Field().String()) >= 5 && len(fl.Field().String()) <= 1500
 	})
 
+	_ = newValidator.RegisterValidation("duration", func(fl validator.FieldLevel) bool {
+		duration, err := time.ParseDuration(fl.Field().String())
+		return err == nil && duration >= time.Second && duration <= 24*time.Hour
+	})
+
+	policies := map[string]func(value string) []errorz.FieldError{
+		"password": func(value string) []errorz.FieldError { return passwordPolicy.Check(value) },
+		"username": usernameRules.Check,
+	}
+	for rule, check := range policies {
+		_ = newValidator.RegisterValidation(rule, func(fl validator.FieldLevel) bool {
+			return len(check(fl.Field().String())) == 0
+		})
+	}
+
 	return &Validator{
-		newValidator,
+		validator: newValidator,
+		policies:  policies,
 	}
 }
 
-func (v Validator) ValidateData(data interface{}) *fiber.Error {
-	var validationErrors []ErrorResponse
-
+// ValidateData is a method that validates a struct using its validate tags, it returns an errorz.CodeValidation error.
+// Field values are never included in the error, so passwords and codes can't leak into responses or logs.
+func (v Validator) ValidateData(data interface{}) error {
 	errs := v.validator.Struct(data)
-	if errs != nil {
-		for _, err := range errs.(validator.ValidationErrors) {
-			var elem ErrorResponse
+	if errs == nil {
+		return nil
+	}
 
-			elem.FailedField = err.Field() // Export struct field name
-			elem.Tag = err.Tag()           // Export struct tag
-			elem.Value = err.Value()       // Export field value
-			elem.Error = true
+	var validationErrors validator.ValidationErrors
+	if !errors.As(errs, &validationErrors) {
+		return errorz.Internal(errs)
+	}
 
-			validationErrors = append(validationErrors, elem)
+	fields := make([]errorz.FieldError, 0, len(validationErrors))
+	for _, err := range validationErrors {
+		field := fieldName(err)
+
+		// Report every failed policy requirement instead of the single rule
+		if check, ok := v.policies[err.Tag()]; ok {
+			if value, isString := err.Value().(string); isString {
+				for _, violation := range check(value) {
+					violation.Field = field
+					fields = append(fields, violation)
+				}
+				continue
+			}
 		}
+
+		params := fieldParams(err)
+
+		fields = append(fields, errorz.FieldError{
+			Field:   field,
+			Rule:    err.Tag(),
+			Message: Message(DefaultLocale, err.Tag(), field, params),
+			Params:  params,
+		})
 	}
 
-	if len(validationErrors) > 0 && validationErrors[0].Error {
-		errMessages := make([]string, 0)
+	return errorz.Validation("validation failed", fields)
+}
+
+// Localize is a function that returns a copy of field errors with messages rendered in the given locale.
+func Localize(fields []errorz.FieldError, locale string) []errorz.FieldError {
+	localized := make([]errorz.FieldError, 0, len(fields))
+	for _, field := range fields {
+		field.Message = Message(locale, field.Rule, field.Field, field.Params)
+		localized = append(localized, field)
+	}
+	return localized
+}
 
-		for _, err := range validationErrors {
-			errMessages = append(errMessages, fmt.Sprintf(
-				"[%s]: '%v' | Needs to implement '%s'",
-				err.FailedField,
-				err.Value,
-				err.Tag,
-			))
-		}
+// fieldName is a function that returns the json path of a failed field without the root struct name, e.g. "events[0].email".
+func fieldName(err validator.FieldError) string {
+	namespace := err.Namespace()
+	if i := strings.Index(namespace, "."); i != -1 {
+		return namespace[i+1:]
+	}
+	return err.Field()
+}
 
-		return &fiber.Error{
-			Code:    fiber.ErrBadRequest.Code,
-			Message: strings.Join(errMessages, " and "),
-		}
+// fieldParams is a function that returns the params of a failed rule: the tag param for built-in rules, fixed limits for custom ones.
+func fieldParams(err validator.FieldError) map[string]string {
+	if params, ok := ruleParams[err.Tag()]; ok {
+		return params
+	}
+	if err.Param() != "" {
+		return map[string]string{"value": err.Param()}
 	}
 	return nil
 }
 
-func (v Validator) GetLimitAndOffset(c *fiber.Ctx, defaultLimit string, defaultOffset string) (int, int) {
-	limit, err := strconv.Atoi(c.Query("limit", defaultLimit))
-	if err != nil {
-		return 0, 10
+// GetLimitAndOffset is a method that reads the page of a list from the limit and offset query params.
+// Values that aren't integers are rejected with an errorz.CodeValidation error, others are clamped:
+// the limit to 1..MaxLimit and the offset to 0 or more.
+func (v Validator) GetLimitAndOffset(c *fiber.Ctx, defaultLimit int, defaultOffset int) (int, int, error) {
+	var fields []errorz.FieldError
+	limit, ok := queryInt(c, "limit", defaultLimit)
+	if !ok {
+		fields = append(fields, integerFieldError("limit"))
+	}
+	offset, ok := queryInt(c, "offset", defaultOffset)
+	if !ok {
+		fields = append(fields, integerFieldError("offset"))
+	}
+	if len(fields) > 0 {
+		return 0, 0, errorz.Validation("validation failed", fields)
 	}
-	offset, err := strconv.Atoi(c.Query("offset", defaultOffset))
-	if err != nil {
-		return 0, 10
+
+	return min(max(limit, 1), MaxLimit), max(offset, 0), nil
+}
+
+// queryInt is a function that returns an integer query param, or the default value if it is absent.
+func queryInt(c *fiber.Ctx, name string, defaultValue int) (int, bool) {
+	value := c.Query(name)
+	if value == "" {
+		return defaultValue, true
+	}
+	number, err := strconv.Atoi(value)
+	return number, err == nil
+}
+
+// integerFieldError is a function that returns the error of a field that must be an integer.
+func integerFieldError(field string) errorz.FieldError {
+	return errorz.FieldError{
+		Field:   field,
+		Rule:    "integer",
+		Message: Message(DefaultLocale, "integer", field, nil),
+	}
+}
+
+// NewFieldError is a function that creates the field error of a rule checked outside of struct validation.
+func NewFieldError(field string, rule string) errorz.FieldError {
+	params := ruleParams[rule]
+	return errorz.FieldError{
+		Field:   field,
+		Rule:    rule,
+		Message: Message(DefaultLocale, rule, field, params),
+		Params:  params,
 	}
-	return limit, offset
 }
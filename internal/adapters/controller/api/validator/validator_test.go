@@ -0,0 +1,64 @@
+package validator
+
+import (
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"github.com/valyala/fasthttp"
+	"testing"
+	"webTemplate/internal/domain/common/errorz"
+)
+
+func TestGetLimitAndOffset(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       string
+		wantLimit   int
+		wantOffset  int
+		wantInvalid []string
+	}{
+		{name: "defaults", query: "", wantLimit: 10, wantOffset: 0},
+		{name: "valid", query: "limit=25&offset=50", wantLimit: 25, wantOffset: 50},
+		{name: "zero limit", query: "limit=0", wantLimit: 1, wantOffset: 0},
+		{name: "negative limit", query: "limit=-1", wantLimit: 1, wantOffset: 0},
+		{name: "large limit", query: "limit=100000", wantLimit: MaxLimit, wantOffset: 0},
+		{name: "negative offset", query: "offset=-5", wantLimit: 10, wantOffset: 0},
+		{name: "malformed limit", query: "limit=abc", wantInvalid: []string{"limit"}},
+		{name: "malformed offset", query: "offset=1.5", wantInvalid: []string{"offset"}},
+		{name: "both malformed", query: "limit=ten&offset=x", wantInvalid: []string{"limit", "offset"}},
+	}
+
+	app := fiber.New()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requestCtx := &fasthttp.RequestCtx{}
+			requestCtx.Request.SetRequestURI("/users?" + tt.query)
+			c := app.AcquireCtx(requestCtx)
+			defer app.ReleaseCtx(c)
+
+			limit, offset, err := Validator{}.GetLimitAndOffset(c, 10, 0)
+
+			if len(tt.wantInvalid) > 0 {
+				var domainErr *errorz.Error
+				if !errors.As(err, &domainErr) || domainErr.Code != errorz.CodeValidation {
+					t.Fatalf("error = %v, want a validation error", err)
+				}
+				if len(domainErr.Fields) != len(tt.wantInvalid) {
+					t.Fatalf("fields = %+v, want %v", domainErr.Fields, tt.wantInvalid)
+				}
+				for i, field := range domainErr.Fields {
+					if field.Field != tt.wantInvalid[i] || field.Rule != "integer" {
+						t.Errorf("field %d = %+v, want %s integer", i, field, tt.wantInvalid[i])
+					}
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if limit != tt.wantLimit || offset != tt.wantOffset {
+				t.Errorf("limit, offset = %d, %d, want %d, %d", limit, offset, tt.wantLimit, tt.wantOffset)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package memory
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+	"webTemplate/internal/domain/entity"
+)
+
+// emailEventStorage is a struct that keeps email events in a slice, it behaves like the postgres email event storage.
+type emailEventStorage struct {
+	mu     sync.RWMutex
+	events []entity.EmailEvent
+}
+
+// NewEmailEventStorage is a function that returns a new empty instance of emailEventStorage.
+func NewEmailEventStorage() *emailEventStorage {
+	return &emailEventStorage{}
+}
+
+// Create is a method to store a new EmailEvent, its id and creation time are generated like database defaults.
+func (s *emailEventStorage) Create(_ context.Context, event entity.EmailEvent) (*entity.EmailEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if event.ID == "" {
+		event.ID = uuid.NewString()
+	}
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+
+	stored := event
+	stored.User = nil
+	if event.UserID != nil {
+		userID := *event.UserID
+		stored.UserID = &userID
+	}
+	s.events = append(s.events, stored)
+	return &event, nil
+}
+
+// GetByUserID is a method that returns the latest EmailEvent instances of a user, newest first.
+func (s *emailEventStorage) GetByUserID(_ context.Context, userID string, limit int) ([]entity.EmailEvent, error) {
+	s.mu.RLock()
+	var events []entity.EmailEvent
+	for _, event := range s.events {
+		if event.UserID != nil && *event.UserID == userID {
+			events = append(events, event)
+		}
+	}
+	s.mu.RUnlock()
+
+	sort.SliceStable(events, func(i, j int) bool { return events[i].OccurredAt.After(events[j].OccurredAt) })
+	// Negative limit is ignored, as it is by gorm
+	if limit >= 0 {
+		events = events[:min(limit, len(events))]
+	}
+	return events, nil
+}
+
+// snapshot is a method that copies the events and returns a function restoring them.
+func (s *emailEventStorage) snapshot() func() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	events := slices.Clone(s.events)
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.events = events
+	}
+}
@@ -0,0 +1,6 @@
+package memory
+
+import "webTemplate/internal/domain/common/errorz"
+
+// errAlreadyExists is the error of a violated unique constraint, the same as the one returned by database storages.
+var errAlreadyExists = errorz.New(errorz.CodeConflict, "already exists")
@@ -0,0 +1,120 @@
+package memory
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"maps"
+	"sort"
+	"sync"
+	"time"
+	"webTemplate/internal/domain/entity"
+)
+
+// outboxStorage is a struct that keeps outbox emails in a map, it behaves like the postgres outbox storage.
+type outboxStorage struct {
+	mu     sync.Mutex
+	emails map[string]entity.OutboxEmail
+}
+
+// NewOutboxStorage is a function that returns a new empty instance of outboxStorage.
+func NewOutboxStorage() *outboxStorage {
+	return &outboxStorage{emails: make(map[string]entity.OutboxEmail)}
+}
+
+// Create is a method to store a new OutboxEmail, its id and timestamps are generated like database defaults.
+func (s *outboxStorage) Create(_ context.Context, email entity.OutboxEmail) (*entity.OutboxEmail, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if email.ID == "" {
+		email.ID = uuid.NewString()
+	} else if _, exists := s.emails[email.ID]; exists {
+		return nil, errAlreadyExists
+	}
+	now := time.Now()
+	if email.CreatedAt.IsZero() {
+		email.CreatedAt = now
+	}
+	if email.UpdatedAt.IsZero() {
+		email.UpdatedAt = now
+	}
+
+	s.emails[email.ID] = email
+	return &email, nil
+}
+
+// Claim is a method that leases up to limit due OutboxEmail instances for the lease duration and returns them,
+// the earliest due first.
+func (s *outboxStorage) Claim(_ context.Context, limit int, lease time.Duration) ([]entity.OutboxEmail, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	var emails []entity.OutboxEmail
+	for _, email := range s.emails {
+		if email.SentAt == nil && email.FailedAt == nil && !email.NextAttemptAt.After(now) {
+			emails = append(emails, email)
+		}
+	}
+	sort.Slice(emails, func(i, j int) bool { return emails[i].NextAttemptAt.Before(emails[j].NextAttemptAt) })
+	emails = emails[:min(limit, len(emails))]
+
+	for i := range emails {
+		emails[i].NextAttemptAt = now.Add(lease)
+		emails[i].UpdatedAt = now
+		s.emails[emails[i].ID] = emails[i]
+	}
+	return emails, nil
+}
+
+// MarkSent is a method to mark an OutboxEmail as successfully sent.
+func (s *outboxStorage) MarkSent(_ context.Context, id string) error {
+	now := time.Now()
+	s.update(id, func(email *entity.OutboxEmail) {
+		email.SentAt = &now
+		email.Attempts++
+	})
+	return nil
+}
+
+// MarkFailed is a method to record a failed attempt, nil nextAttemptAt marks the OutboxEmail as failed permanently.
+func (s *outboxStorage) MarkFailed(_ context.Context, id string, lastError string, nextAttemptAt *time.Time) error {
+	now := time.Now()
+	s.update(id, func(email *entity.OutboxEmail) {
+		email.LastError = lastError
+		email.Attempts++
+		if nextAttemptAt != nil {
+			email.NextAttemptAt = *nextAttemptAt
+		} else {
+			email.FailedAt = &now
+		}
+	})
+	return nil
+}
+
+// update is a method that changes a stored email, missing emails are skipped like updates of no rows.
+func (s *outboxStorage) update(id string, change func(email *entity.OutboxEmail)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	email, ok := s.emails[id]
+	if !ok {
+		return
+	}
+	change(&email)
+	email.UpdatedAt = time.Now()
+	s.emails[id] = email
+}
+
+// snapshot is a method that copies the emails and returns a function restoring them.
+func (s *outboxStorage) snapshot() func() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	emails := maps.Clone(s.emails)
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.emails = emails
+	}
+}
@@ -0,0 +1,23 @@
+package memory
+
+import (
+	"context"
+	"testing"
+	"webTemplate/internal/adapters/database/storagetest"
+)
+
+func TestStorageContract(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) (context.Context, storagetest.Storages) {
+		users := NewUserStorage()
+		tokens := NewTokenStorage()
+		outbox := NewOutboxStorage()
+		emailEvents := NewEmailEventStorage()
+		return context.Background(), storagetest.Storages{
+			Users:       users,
+			Tokens:      tokens,
+			Outbox:      outbox,
+			EmailEvents: emailEvents,
+			Transactor:  NewTransactionManager(users, tokens, outbox, emailEvents),
+		}
+	})
+}
@@ -0,0 +1,114 @@
+package memory
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"maps"
+	"sync"
+	"time"
+	"webTemplate/internal/domain/common/errorz"
+	"webTemplate/internal/domain/entity"
+)
+
+// tokenStorage is a struct that keeps tokens in a map, it behaves like the postgres token storage.
+type tokenStorage struct {
+	mu     sync.RWMutex
+	tokens map[string]entity.Token
+}
+
+// NewTokenStorage is a function that returns a new empty instance of tokenStorage.
+func NewTokenStorage() *tokenStorage {
+	return &tokenStorage{tokens: make(map[string]entity.Token)}
+}
+
+// Create is a method to store a new Token, its id and timestamps are generated like database defaults.
+func (s *tokenStorage) Create(_ context.Context, token entity.Token) (*entity.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if token.ID == "" {
+		token.ID = uuid.NewString()
+	} else if _, exists := s.tokens[token.ID]; exists {
+		return nil, errAlreadyExists
+	}
+	now := time.Now()
+	if token.CreatedAt.IsZero() {
+		token.CreatedAt = now
+	}
+	if token.UpdatedAt.IsZero() {
+		token.UpdatedAt = now
+	}
+
+	stored := token
+	stored.User = nil
+	s.tokens[token.ID] = stored
+	return &token, nil
+}
+
+// GetByUserID is a method that returns an error and a pointer to a not expired Token instance by user id and token type.
+func (s *tokenStorage) GetByUserID(_ context.Context, userID string, tokenType string) (*entity.Token, error) {
+	return s.find(func(token entity.Token) bool { return token.UserID == userID && token.Type == tokenType })
+}
+
+// GetByToken is a method that returns an error and a pointer to a not expired Token instance by token string and token type.
+func (s *tokenStorage) GetByToken(_ context.Context, token string, tokenType string) (*entity.Token, error) {
+	return s.find(func(stored entity.Token) bool { return stored.Token == token && stored.Type == tokenType })
+}
+
+// DeleteAll is a method to delete all user Tokens.
+func (s *tokenStorage) DeleteAll(_ context.Context, userID string) error {
+	return s.delete(func(token entity.Token) bool { return token.UserID == userID })
+}
+
+// Delete is a method to delete an existing Token by user id and token type.
+func (s *tokenStorage) Delete(_ context.Context, userID string, tokenType string) error {
+	return s.delete(func(token entity.Token) bool { return token.UserID == userID && token.Type == tokenType })
+}
+
+// find is a method that returns a copy of the not expired token with the lowest id matching the predicate,
+// like gorm First, or errorz.TokenNotFound.
+func (s *tokenStorage) find(match func(token entity.Token) bool) (*entity.Token, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
+	var found *entity.Token
+	for _, token := range s.tokens {
+		if !token.Expires.After(now) || !match(token) {
+			continue
+		}
+		if found == nil || token.ID < found.ID {
+			found = &token
+		}
+	}
+	if found == nil {
+		return nil, errorz.TokenNotFound
+	}
+	return found, nil
+}
+
+// delete is a method that deletes all tokens matching the predicate.
+func (s *tokenStorage) delete(match func(token entity.Token) bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, token := range s.tokens {
+		if match(token) {
+			delete(s.tokens, id)
+		}
+	}
+	return nil
+}
+
+// snapshot is a method that copies the tokens and returns a function restoring them.
+func (s *tokenStorage) snapshot() func() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tokens := maps.Clone(s.tokens)
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.tokens = tokens
+	}
+}
@@ -0,0 +1,56 @@
+package memory
+
+import (
+	"context"
+	"sync"
+)
+
+type txKey struct{}
+
+// snapshotter is an interface of the memory storages that can be restored when a transaction fails.
+type snapshotter interface {
+	snapshot() (restore func())
+}
+
+// transactionManager is a struct that runs functions in transactions over memory storages.
+// Transactions run one at a time, a failed one restores the storages as they were when it started.
+type transactionManager struct {
+	mu       sync.Mutex
+	storages []snapshotter
+}
+
+// NewTransactionManager is a function that returns a new instance of transactionManager for the storages
+// created by this package.
+func NewTransactionManager(storages ...snapshotter) *transactionManager {
+	return &transactionManager{storages: storages}
+}
+
+// Do is a method that runs fn in a transaction, kept if fn returns nil and rolled back otherwise or on panic.
+// Nested calls roll back only their own changes, like savepoints. Changes made outside of transactions
+// while one is rolled back are lost, so the memory storages suit tests and local development only.
+func (m *transactionManager) Do(ctx context.Context, fn func(ctx context.Context) error) (err error) {
+	if ctx.Value(txKey{}) == nil {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		ctx = context.WithValue(ctx, txKey{}, m)
+	}
+
+	restores := make([]func(), len(m.storages))
+	for i, storage := range m.storages {
+		restores[i] = storage.snapshot()
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			for _, restore := range restores {
+				restore()
+			}
+		}
+	}()
+
+	if err = fn(ctx); err != nil {
+		return err
+	}
+	committed = true
+	return nil
+}
@@ -0,0 +1,211 @@
+package memory
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"maps"
+	"sort"
+	"sync"
+	"time"
+	"webTemplate/internal/domain/common/errorz"
+	"webTemplate/internal/domain/entity"
+	"webTemplate/internal/domain/utils/username"
+)
+
+// userStorage is a struct that keeps users in a map, it behaves like the postgres user storage.
+type userStorage struct {
+	mu    sync.RWMutex
+	users map[string]entity.User
+}
+
+// NewUserStorage is a function that returns a new empty instance of userStorage.
+func NewUserStorage() *userStorage {
+	return &userStorage{users: make(map[string]entity.User)}
+}
+
+// Create is a method to store a new User, its id and timestamps are generated like database defaults.
+func (s *userStorage) Create(_ context.Context, user entity.User) (*entity.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	emailCanonical := username.CanonicalEmail(user.Email)
+	for _, stored := range s.users {
+		if stored.VerifiedEmail && stored.EmailCanonical == emailCanonical {
+			return nil, errorz.EmailAlreadyTaken
+		}
+	}
+
+	if user.ID == "" {
+		user.ID = uuid.NewString()
+	} else if _, exists := s.users[user.ID]; exists {
+		return nil, errAlreadyExists
+	}
+	now := time.Now()
+	if user.CreatedAt.IsZero() {
+		user.CreatedAt = now
+	}
+	if user.UpdatedAt.IsZero() {
+		user.UpdatedAt = now
+	}
+	if user.VerificationCode == "" {
+		user.VerificationCode = "NULL"
+	}
+	if user.Role == "" {
+		user.Role = entity.RoleUser
+	}
+	if user.EmailDeliveryState == "" {
+		user.EmailDeliveryState = entity.EmailDeliveryUnknown
+	}
+	if err := s.checkUnique(user); err != nil {
+		return nil, err
+	}
+
+	s.users[user.ID] = cloneUser(user)
+	return &user, nil
+}
+
+// GetByID is a method that returns an error and a pointer to a User instance by id.
+func (s *userStorage) GetByID(_ context.Context, id string) (*entity.User, error) {
+	return s.find(func(user entity.User) bool { return user.ID == id })
+}
+
+// GetAll is a method that returns a page of users in the order of creation.
+func (s *userStorage) GetAll(_ context.Context, limit, offset int) ([]entity.User, error) {
+	s.mu.RLock()
+	users := make([]entity.User, 0, len(s.users))
+	for _, user := range s.users {
+		users = append(users, cloneUser(user))
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(users, func(i, j int) bool {
+		if !users[i].CreatedAt.Equal(users[j].CreatedAt) {
+			return users[i].CreatedAt.Before(users[j].CreatedAt)
+		}
+		return users[i].ID < users[j].ID
+	})
+
+	// Negative limit and offset are ignored, as they are by gorm
+	if offset > 0 {
+		users = users[min(offset, len(users)):]
+	}
+	if limit >= 0 {
+		users = users[:min(limit, len(users))]
+	}
+	return users, nil
+}
+
+// Update is a method to update the non-zero fields of an existing User, like gorm Updates with a struct.
+func (s *userStorage) Update(_ context.Context, user *entity.User) (*entity.User, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.users[user.ID]
+	if !ok {
+		return user, nil
+	}
+
+	user.UpdatedAt = time.Now()
+	stored.UpdatedAt = user.UpdatedAt
+	setString(&stored.Email, user.Email)
+	setString(&stored.VerificationCode, user.VerificationCode)
+	setString(&stored.Role, user.Role)
+	setString(&stored.Username, user.Username)
+	setString(&stored.EmailCanonical, user.EmailCanonical)
+	setString(&stored.UsernameCanonical, user.UsernameCanonical)
+	setString(&stored.EmailDeliveryState, user.EmailDeliveryState)
+	if user.VerifiedEmail {
+		stored.VerifiedEmail = true
+	}
+	if len(user.Password) > 0 {
+		stored.Password = user.Password
+	}
+	if user.EmailDeliveryUpdatedAt != nil {
+		stored.EmailDeliveryUpdatedAt = user.EmailDeliveryUpdatedAt
+	}
+	if err := s.checkUnique(stored); err != nil {
+		return nil, err
+	}
+
+	s.users[stored.ID] = cloneUser(stored)
+	return user, nil
+}
+
+// Delete is a method to delete an existing User.
+func (s *userStorage) Delete(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.users, id)
+	return nil
+}
+
+// GetByEmail is a method that returns a pointer to a User instance and error by case-insensitive email.
+func (s *userStorage) GetByEmail(_ context.Context, email string) (*entity.User, error) {
+	emailCanonical := username.CanonicalEmail(email)
+	return s.find(func(user entity.User) bool { return user.EmailCanonical == emailCanonical })
+}
+
+// GetByUsername is a method that returns a pointer to a User instance and error by canonical username.
+func (s *userStorage) GetByUsername(_ context.Context, name string) (*entity.User, error) {
+	canonical := username.Canonical(name)
+	return s.find(func(user entity.User) bool { return user.UsernameCanonical == canonical })
+}
+
+// find is a method that returns a copy of the first user matching the predicate, or errorz.UserNotFound.
+func (s *userStorage) find(match func(user entity.User) bool) (*entity.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, user := range s.users {
+		if match(user) {
+			found := cloneUser(user)
+			return &found, nil
+		}
+	}
+	return nil, errorz.UserNotFound
+}
+
+// checkUnique is a method that enforces the unique indexes of the users table, the user itself is skipped.
+func (s *userStorage) checkUnique(user entity.User) error {
+	for _, stored := range s.users {
+		if stored.ID == user.ID {
+			continue
+		}
+		if stored.EmailCanonical == user.EmailCanonical || stored.UsernameCanonical == user.UsernameCanonical {
+			return errAlreadyExists
+		}
+	}
+	return nil
+}
+
+// snapshot is a method that copies the users and returns a function restoring them.
+func (s *userStorage) snapshot() func() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	users := maps.Clone(s.users)
+	return func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.users = users
+	}
+}
+
+// cloneUser is a function that copies a user, so callers can't change stored users through shared slices.
+func cloneUser(user entity.User) entity.User {
+	user.Password = append([]byte(nil), user.Password...)
+	user.Token = nil
+	if user.EmailDeliveryUpdatedAt != nil {
+		updatedAt := *user.EmailDeliveryUpdatedAt
+		user.EmailDeliveryUpdatedAt = &updatedAt
+	}
+	return user
+}
+
+// setString is a function that overwrites a stored field with a non-empty value.
+func setString(field *string, value string) {
+	if value != "" {
+		*field = value
+	}
+}
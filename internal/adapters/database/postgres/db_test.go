@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
+	"os"
+	"strings"
+	"testing"
+	"webTemplate/internal/adapters/logger"
+)
+
+// testDSNEnv is the environment variable with the DSN of a postgres database for tests, they are skipped without it.
+const testDSNEnv = "APP_TEST_DATABASE_DSN"
+
+// openTestDB is a function that connects to the test database with a new empty schema as the search path.
+// The schema is dropped when the test completes.
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv(testDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", testDSNEnv)
+	}
+	if logger.Log == nil {
+		if err := logger.New(logger.Options{Outputs: []string{"stderr"}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	admin, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: gormLogger.Discard})
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	schema := "test_" + strings.ReplaceAll(uuid.NewString(), "-", "")
+	if err = admin.Exec(`CREATE SCHEMA "` + schema + `"`).Error; err != nil {
+		t.Fatalf("Create schema: %v", err)
+	}
+	t.Cleanup(func() {
+		admin.Exec(`DROP SCHEMA "` + schema + `" CASCADE`)
+		if sqlDB, err := admin.DB(); err == nil {
+			_ = sqlDB.Close()
+		}
+	})
+
+	connConfig, err := pgx.ParseConfig(dsn)
+	if err != nil {
+		t.Fatalf("Parse DSN: %v", err)
+	}
+	connConfig.RuntimeParams["search_path"] = schema
+	sqlDB := stdlib.OpenDB(*connConfig)
+	t.Cleanup(func() { _ = sqlDB.Close() })
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{TranslateError: true, Logger: gormLogger.Discard})
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	return db
+}
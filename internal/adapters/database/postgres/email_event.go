@@ -0,0 +1,33 @@
+package postgres
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"webTemplate/internal/domain/common/errorz"
+	"webTemplate/internal/domain/entity"
+)
+
+// emailEventStorage is a struct that contains a pointer to a gorm.DB instance to interact with email event repository.
+type emailEventStorage struct {
+	db *gorm.DB
+}
+
+// NewEmailEventStorage is a function that returns a new instance of emailEventStorage.
+func NewEmailEventStorage(db *gorm.DB) *emailEventStorage {
+	return &emailEventStorage{db: db}
+}
+
+// Create is a method to create a new EmailEvent in database.
+func (s *emailEventStorage) Create(ctx context.Context, event entity.EmailEvent) (*entity.EmailEvent, error) {
+	err := contextDB(ctx, s.db).Create(&event).Error
+	return &event, storageError(err, errorz.NotFound)
+}
+
+// GetByUserID is a method that returns the latest EmailEvent instances of a user, newest first.
+func (s *emailEventStorage) GetByUserID(ctx context.Context, userID string, limit int) ([]entity.EmailEvent, error) {
+	var events []entity.EmailEvent
+	err := contextDB(ctx, s.db).Model(&entity.EmailEvent{}).Where(
+		"user_id = ?", userID,
+	).Order("occurred_at DESC").Limit(limit).Find(&events).Error
+	return events, storageError(err, errorz.NotFound)
+}
@@ -0,0 +1,15 @@
+package postgres
+
+import (
+	"context"
+	"gorm.io/gorm"
+)
+
+// Ping is a function that checks that the database accepts connections.
+func Ping(ctx context.Context, db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
@@ -1,9 +1,307 @@
 package postgres
 
-import "webTemplate/internal/domain/entity"
+import (
+	"context"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"gorm.io/gorm"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+	"webTemplate/internal/adapters/logger"
+	"webTemplate/internal/domain/entity"
+	"webTemplate/internal/domain/utils/username"
+)
 
-// Migrations is a list of all gorm migrations for the database.
-var Migrations = []interface{}{
-	&entity.User{},
-	&entity.Token{},
+// migrationLockID is the key of the postgres advisory lock held while migrating, so replicas starting together
+// apply every migration once. The value is arbitrary, it must only differ from other advisory locks of the database.
+const migrationLockID int64 = 7_316_845_012
+
+// migrationFiles contains the migrations as "<version>_<name>.up.sql" and "<version>_<name>.down.sql" files.
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationHooks is a map of versions to changes made in Go after the SQL of the migration, in its transaction.
+// They are run only when the migration is applied, keep them working with the schema of that version.
+var migrationHooks = map[int64]func(tx *gorm.DB) error{
+	// Existing users get canonical names before 0003 makes them unique
+	2: BackfillCanonicalNames,
+}
+
+// migrationFilePattern matches migration file names, e.g. "0002_add_user_locale.up.sql".
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration is a struct that contains a versioned schema change.
+type Migration struct {
+	Version  int64
+	Name     string
+	Up       string
+	Down     string
+	Checksum string // Hex sha256 of Up, an applied migration must not change
+}
+
+// MigrationStatus is a struct that contains a migration and whether it is applied.
+// Migrations applied by a newer version of the app have no Up and Down.
+type MigrationStatus struct {
+	Migration
+	AppliedAt *time.Time
+	Changed   bool // The file differs from the applied migration
+}
+
+// schemaMigration is a struct that represents an applied migration in database.
+type schemaMigration struct {
+	Version   int64 `gorm:"primaryKey;autoIncrement:false"`
+	Name      string
+	Checksum  string
+	AppliedAt time.Time
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// LoadMigrations is a function that returns the embedded migrations ordered by version.
+func LoadMigrations() ([]Migration, error) {
+	files, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, file := range files {
+		match := migrationFilePattern.FindStringSubmatch(file.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %q", file.Name())
+		}
+		version, _ := strconv.ParseInt(match[1], 10, 64)
+		content, err := migrationFiles.ReadFile(path.Join("migrations", file.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has two names: %s and %s", version, migration.Name, match[2])
+		}
+		if match[3] == "up" {
+			migration.Up = string(content)
+			checksum := sha256.Sum256(content)
+			migration.Checksum = hex.EncodeToString(checksum[:])
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d_%s needs both an up and a down file", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// Migrate is a function that applies all pending migrations, each in its own transaction with its hook,
+// then backfills canonical names of users created by older app versions meanwhile.
+// It fails if an applied migration was changed.
+func Migrate(ctx context.Context, db *gorm.DB) error {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return err
+	}
+
+	logger.Log.Info("Running migrations...")
+	err = withMigrationLock(ctx, db, func(conn *gorm.DB) error {
+		applied, err := appliedMigrations(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range migrations {
+			if record, ok := applied[migration.Version]; ok {
+				if record.Checksum != migration.Checksum {
+					return fmt.Errorf("applied migration %d_%s was changed, add a new migration instead", migration.Version, migration.Name)
+				}
+				continue
+			}
+
+			logger.Log.Infof("Applying migration %d_%s", migration.Version, migration.Name)
+			if err = conn.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Exec(migration.Up).Error; err != nil {
+					return err
+				}
+				if hook, ok := migrationHooks[migration.Version]; ok {
+					if err := hook(tx); err != nil {
+						return err
+					}
+				}
+				return tx.Create(&schemaMigration{
+					Version:   migration.Version,
+					Name:      migration.Name,
+					Checksum:  migration.Checksum,
+					AppliedAt: time.Now().UTC(),
+				}).Error
+			}); err != nil {
+				return fmt.Errorf("failed to apply migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = BackfillCanonicalNames(db.WithContext(ctx)); err != nil {
+		return fmt.Errorf("failed to backfill canonical user names: %w", err)
+	}
+	logger.Log.Info("Migrations finished")
+	return nil
+}
+
+// MigrateDown is a function that rolls back the last applied migrations, each in its own transaction.
+/*
+ * steps int - the number of migrations to roll back
+ */
+func MigrateDown(ctx context.Context, db *gorm.DB, steps int) error {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return err
+	}
+	byVersion := make(map[int64]Migration, len(migrations))
+	for _, migration := range migrations {
+		byVersion[migration.Version] = migration
+	}
+
+	return withMigrationLock(ctx, db, func(conn *gorm.DB) error {
+		var applied []schemaMigration
+		if err := conn.Order("version DESC").Limit(steps).Find(&applied).Error; err != nil {
+			return err
+		}
+
+		for _, record := range applied {
+			migration, ok := byVersion[record.Version]
+			if !ok {
+				return fmt.Errorf("migration %d_%s has no file, it was applied by a newer version of the app", record.Version, record.Name)
+			}
+
+			logger.Log.Infof("Rolling back migration %d_%s", migration.Version, migration.Name)
+			if err := conn.Transaction(func(tx *gorm.DB) error {
+				if err := tx.Exec(migration.Down).Error; err != nil {
+					return err
+				}
+				return tx.Delete(&schemaMigration{}, "version = ?", migration.Version).Error
+			}); err != nil {
+				return fmt.Errorf("failed to roll back migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+		}
+		return nil
+	})
+}
+
+// MigrationStatuses is a function that returns the embedded and the applied migrations ordered by version.
+func MigrationStatuses(ctx context.Context, db *gorm.DB) ([]MigrationStatus, error) {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []MigrationStatus
+	err = withMigrationLock(ctx, db, func(conn *gorm.DB) error {
+		applied, err := appliedMigrations(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range migrations {
+			status := MigrationStatus{Migration: migration}
+			if record, ok := applied[migration.Version]; ok {
+				status.AppliedAt = &record.AppliedAt
+				status.Changed = record.Checksum != migration.Checksum
+				delete(applied, migration.Version)
+			}
+			statuses = append(statuses, status)
+		}
+		for _, record := range applied {
+			statuses = append(statuses, MigrationStatus{
+				Migration: Migration{Version: record.Version, Name: record.Name, Checksum: record.Checksum},
+				AppliedAt: &record.AppliedAt,
+			})
+		}
+		return nil
+	})
+
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Version < statuses[j].Version
+	})
+	return statuses, err
+}
+
+// withMigrationLock is a function that runs fn on a single connection holding the migration advisory lock,
+// the schema_migrations table is created first if needed.
+// Waiting for the lock and migrations may take long, the default query timeout doesn't apply to them.
+func withMigrationLock(ctx context.Context, db *gorm.DB, fn func(conn *gorm.DB) error) error {
+	return WithoutQueryTimeout(db).WithContext(ctx).Connection(func(conn *gorm.DB) error {
+		if err := conn.Exec("SELECT pg_advisory_lock(?)", migrationLockID).Error; err != nil {
+			return fmt.Errorf("failed to lock migrations: %w", err)
+		}
+		defer conn.Exec("SELECT pg_advisory_unlock(?)", migrationLockID)
+
+		if err := conn.Exec(`CREATE TABLE IF NOT EXISTS "schema_migrations" (
+			"version"    bigint      NOT NULL PRIMARY KEY,
+			"name"       text        NOT NULL,
+			"checksum"   text        NOT NULL,
+			"applied_at" timestamptz NOT NULL
+		)`).Error; err != nil {
+			return fmt.Errorf("failed to create schema_migrations: %w", err)
+		}
+		return fn(conn)
+	})
+}
+
+// appliedMigrations is a function that returns a map of versions to applied migrations.
+func appliedMigrations(ctx context.Context, conn *gorm.DB) (map[int64]schemaMigration, error) {
+	var records []schemaMigration
+	if err := conn.WithContext(ctx).Find(&records).Error; err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int64]schemaMigration, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+	return applied, nil
+}
+
+// BackfillCanonicalNames is a function that fills canonical emails and usernames of users created before they were introduced.
+// It fails on users differing only in email or username case, they must be resolved manually.
+func BackfillCanonicalNames(db *gorm.DB) error {
+	var users []entity.User
+	if err := db.Where("email_canonical IS NULL OR username_canonical IS NULL").Find(&users).Error; err != nil {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, user := range users {
+			if err := tx.Model(&entity.User{}).Where("id = ?", user.ID).Updates(map[string]interface{}{
+				"email_canonical":    username.CanonicalEmail(user.Email),
+				"username_canonical": username.Canonical(user.Username),
+			}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
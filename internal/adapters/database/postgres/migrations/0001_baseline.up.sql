@@ -0,0 +1,28 @@
+-- Baseline: the schema created by gorm AutoMigrate from entity.User and entity.Token before versioned migrations.
+-- "IF NOT EXISTS" lets databases created by AutoMigrate adopt it without changes, later migrations bring them up to date.
+
+CREATE TABLE IF NOT EXISTS "users" (
+    "id"                uuid        NOT NULL DEFAULT gen_random_uuid(),
+    "created_at"        timestamptz,
+    "updated_at"        timestamptz,
+    "email"             text,
+    "verified_email"    boolean     NOT NULL DEFAULT false,
+    "verification_code" text        NOT NULL DEFAULT 'NULL',
+    "password"          bytea,
+    "role"              text        NOT NULL DEFAULT 'user',
+    "username"          text,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_users_email" ON "users" ("email");
+
+CREATE TABLE IF NOT EXISTS "tokens" (
+    "id"         uuid        NOT NULL DEFAULT gen_random_uuid(),
+    "created_at" timestamptz,
+    "updated_at" timestamptz,
+    "token"      text        NOT NULL,
+    "user_id"    uuid        NOT NULL,
+    "type"       text        NOT NULL,
+    "expires"    timestamptz NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_users_token" FOREIGN KEY ("user_id") REFERENCES "users" ("id")
+);
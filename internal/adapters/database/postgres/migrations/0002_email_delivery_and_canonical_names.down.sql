@@ -0,0 +1,7 @@
+DROP TABLE IF EXISTS "outbox_emails";
+DROP TABLE IF EXISTS "email_events";
+DROP INDEX IF EXISTS "idx_tokens_token";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "email_delivery_updated_at";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "email_delivery_state";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "username_canonical";
+ALTER TABLE "users" DROP COLUMN IF EXISTS "email_canonical";
@@ -0,0 +1,43 @@
+-- Columns and tables added with email delivery tracking, the email outbox and case-insensitive names.
+-- Databases migrated by AutoMigrate of a newer app version may have some of them, so every change is conditional.
+-- Canonical names of existing users are backfilled in Go after this migration, their unique indexes come next.
+
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "email_canonical" text;
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "username_canonical" text;
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "email_delivery_state" text NOT NULL DEFAULT 'unknown';
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "email_delivery_updated_at" timestamptz;
+
+CREATE INDEX IF NOT EXISTS "idx_tokens_token" ON "tokens" ("token");
+
+CREATE TABLE IF NOT EXISTS "email_events" (
+    "id"          uuid        NOT NULL DEFAULT gen_random_uuid(),
+    "created_at"  timestamptz,
+    "user_id"     uuid,
+    "email"       text        NOT NULL,
+    "type"        text        NOT NULL,
+    "message_id"  text,
+    "reason"      text,
+    "occurred_at" timestamptz NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_email_events_user" FOREIGN KEY ("user_id") REFERENCES "users" ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_email_events_user_id" ON "email_events" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_email_events_email" ON "email_events" ("email");
+CREATE INDEX IF NOT EXISTS "idx_email_events_message_id" ON "email_events" ("message_id");
+
+CREATE TABLE IF NOT EXISTS "outbox_emails" (
+    "id"              uuid        NOT NULL DEFAULT gen_random_uuid(),
+    "created_at"      timestamptz,
+    "updated_at"      timestamptz,
+    "email"           text        NOT NULL,
+    "subject"         text        NOT NULL,
+    "body"            text        NOT NULL,
+    "attempts"        bigint      NOT NULL DEFAULT 0,
+    "next_attempt_at" timestamptz NOT NULL,
+    "sent_at"         timestamptz,
+    "failed_at"       timestamptz,
+    "last_error"      text,
+    PRIMARY KEY ("id")
+);
+ALTER TABLE "outbox_emails" ADD COLUMN IF NOT EXISTS "request_id" text;
+CREATE INDEX IF NOT EXISTS "idx_outbox_emails_next_attempt_at" ON "outbox_emails" ("next_attempt_at");
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS "idx_users_username_canonical";
+DROP INDEX IF EXISTS "idx_users_email_canonical";
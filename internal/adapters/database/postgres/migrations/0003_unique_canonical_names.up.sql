@@ -0,0 +1,4 @@
+-- Emails and usernames are unique case-insensitively, names of existing users were backfilled after 0002.
+
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_email_canonical" ON "users" ("email_canonical");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_username_canonical" ON "users" ("username_canonical");
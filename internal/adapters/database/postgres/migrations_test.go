@@ -0,0 +1,108 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"webTemplate/internal/domain/common/errorz"
+	"webTemplate/internal/domain/entity"
+	"webTemplate/internal/domain/utils/username"
+)
+
+// baselineUser is entity.User as it was created by AutoMigrate before versioned migrations.
+type baselineUser struct {
+	ID        string `gorm:"primaryKey;not null;type:uuid;default:gen_random_uuid()"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	Email            string `gorm:"index"`
+	VerifiedEmail    bool   `gorm:"default:false;not null"`
+	VerificationCode string `gorm:"default:'NULL';not null"`
+	Password         []byte
+	Role             string          `gorm:"default:user;not null"`
+	Token            []baselineToken `gorm:"foreignKey:user_id;references:id"`
+	Username         string
+}
+
+func (baselineUser) TableName() string {
+	return "users"
+}
+
+// baselineToken is entity.Token as it was created by AutoMigrate before versioned migrations.
+type baselineToken struct {
+	ID        string `gorm:"primaryKey;not null;type:uuid;default:gen_random_uuid()"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	Token   string    `gorm:"not null"`
+	UserID  string    `gorm:"not null;type:uuid"`
+	Type    string    `gorm:"not null"`
+	Expires time.Time `gorm:"not null"`
+}
+
+func (baselineToken) TableName() string {
+	return "tokens"
+}
+
+func TestMigrateFromAutoMigrateBaseline(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+
+	if err := db.AutoMigrate(&baselineUser{}, &baselineToken{}); err != nil {
+		t.Fatalf("AutoMigrate: %v", err)
+	}
+	oldUser := baselineUser{Email: "Old.User@Example.com", Username: "OldUser", Password: []byte("hash")}
+	if err := db.Create(&oldUser).Error; err != nil {
+		t.Fatalf("Create old user: %v", err)
+	}
+	if err := db.Create(&baselineToken{Token: "old", UserID: oldUser.ID, Type: "refresh", Expires: time.Now().Add(time.Hour)}).Error; err != nil {
+		t.Fatalf("Create old token: %v", err)
+	}
+
+	if err := Migrate(ctx, db); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+
+	statuses, err := MigrationStatuses(ctx, db)
+	if err != nil {
+		t.Fatalf("MigrationStatuses: %v", err)
+	}
+	for _, status := range statuses {
+		if status.AppliedAt == nil {
+			t.Errorf("migration %d_%s is not applied", status.Version, status.Name)
+		}
+	}
+
+	users := NewUserStorage(db)
+	migrated, err := users.GetByUsername(ctx, "olduser")
+	if err != nil {
+		t.Fatalf("GetByUsername: %v", err)
+	}
+	if migrated.ID != oldUser.ID || migrated.EmailCanonical != "old.user@example.com" || migrated.UsernameCanonical != "olduser" {
+		t.Errorf("migrated user = %+v, want canonical names of %+v", migrated, oldUser)
+	}
+	if migrated.EmailDeliveryState != entity.EmailDeliveryUnknown {
+		t.Errorf("email_delivery_state = %q, want %q", migrated.EmailDeliveryState, entity.EmailDeliveryUnknown)
+	}
+
+	// Canonical names are unique after the migration
+	_, err = users.Create(ctx, entity.User{
+		Email:             "another@example.com",
+		Username:          "OLDUSER",
+		EmailCanonical:    username.CanonicalEmail("another@example.com"),
+		UsernameCanonical: username.Canonical("OLDUSER"),
+	})
+	var domainErr *errorz.Error
+	if !errors.As(err, &domainErr) || domainErr.Code != errorz.CodeConflict {
+		t.Errorf("Create with a taken canonical username: error = %v, want a conflict", err)
+	}
+
+	// Migrations roll back and apply again
+	if err = MigrateDown(ctx, db, len(statuses)); err != nil {
+		t.Fatalf("MigrateDown: %v", err)
+	}
+	if err = Migrate(ctx, db); err != nil {
+		t.Fatalf("Migrate after MigrateDown: %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"time"
+	"webTemplate/internal/domain/common/errorz"
+	"webTemplate/internal/domain/entity"
+)
+
+// outboxStorage is a struct that contains a pointer to a gorm.DB instance to interact with email outbox repository.
+type outboxStorage struct {
+	db *gorm.DB
+}
+
+// NewOutboxStorage is a function that returns a new instance of outboxStorage.
+func NewOutboxStorage(db *gorm.DB) *outboxStorage {
+	return &outboxStorage{db: db}
+}
+
+// Create is a method to create a new OutboxEmail in database.
+func (s *outboxStorage) Create(ctx context.Context, email entity.OutboxEmail) (*entity.OutboxEmail, error) {
+	err := contextDB(ctx, s.db).Create(&email).Error
+	return &email, storageError(err, errorz.NotFound)
+}
+
+// Claim is a method that locks up to limit due OutboxEmail instances for the lease duration and returns them.
+// Claimed rows are skipped by other workers until the lease expires, so several replicas can run the outbox.
+func (s *outboxStorage) Claim(ctx context.Context, limit int, lease time.Duration) ([]entity.OutboxEmail, error) {
+	var emails []entity.OutboxEmail
+	now := time.Now().UTC()
+	err := contextDB(ctx, s.db).Raw(`
+		UPDATE outbox_emails SET next_attempt_at = ?, updated_at = ?
+		WHERE id IN (
+			SELECT id FROM outbox_emails
+			WHERE sent_at IS NULL AND failed_at IS NULL AND next_attempt_at <= ?
+			ORDER BY next_attempt_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, now.Add(lease), now, now, limit,
+	).Scan(&emails).Error
+	return emails, storageError(err, errorz.NotFound)
+}
+
+// MarkSent is a method to mark an OutboxEmail as successfully sent.
+func (s *outboxStorage) MarkSent(ctx context.Context, id string) error {
+	err := contextDB(ctx, s.db).Model(&entity.OutboxEmail{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"sent_at":  time.Now().UTC(),
+		"attempts": gorm.Expr("attempts + 1"),
+	}).Error
+	return storageError(err, errorz.NotFound)
+}
+
+// MarkFailed is a method to record a failed attempt, nil nextAttemptAt marks the OutboxEmail as failed permanently.
+func (s *outboxStorage) MarkFailed(ctx context.Context, id string, lastError string, nextAttemptAt *time.Time) error {
+	updates := map[string]interface{}{
+		"last_error": lastError,
+		"attempts":   gorm.Expr("attempts + 1"),
+	}
+	if nextAttemptAt != nil {
+		updates["next_attempt_at"] = *nextAttemptAt
+	} else {
+		updates["failed_at"] = time.Now().UTC()
+	}
+	err := contextDB(ctx, s.db).Model(&entity.OutboxEmail{}).Where("id = ?", id).Updates(updates).Error
+	return storageError(err, errorz.NotFound)
+}
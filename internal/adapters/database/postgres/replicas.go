@@ -0,0 +1,128 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	gormPostgres "gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+	"slices"
+	"sync"
+	"time"
+)
+
+type stickyKey struct{}
+
+// StickyKey is the key of the read-your-writes keys in fiber locals and contexts, a []string like
+// ["ip:<address>", "user:<id>"]. A write is recorded under every key of its context, a read goes to the primary
+// for a while if any of its keys has written, so a user is sticky after writes made before they authenticated.
+var StickyKey = stickyKey{}
+
+// stickyWrites is a struct that contains the time of the last write of every sticky key.
+type stickyWrites struct {
+	window time.Duration
+
+	mu        sync.Mutex
+	writes    map[string]time.Time
+	lastPrune time.Time
+}
+
+// UseReplicas is a function that routes reads outside of transactions to the replicas, writes, locking reads
+// and everything inside transactions stay on the primary.
+/*
+ * replicas []*sql.DB - pools of the read-only replicas
+ * stickyWindow time.Duration - time reads with a StickyKey go to the primary after a write with the same key, 0 disables it
+ */
+func UseReplicas(db *gorm.DB, replicas []*sql.DB, stickyWindow time.Duration) error {
+	dialectors := make([]gorm.Dialector, 0, len(replicas))
+	for _, replica := range replicas {
+		dialectors = append(dialectors, gormPostgres.New(gormPostgres.Config{Conn: replica}))
+	}
+	if err := db.Use(dbresolver.Register(dbresolver.Config{
+		Replicas: dialectors,
+		Policy:   dbresolver.RandomPolicy{},
+	})); err != nil {
+		return err
+	}
+
+	if stickyWindow <= 0 {
+		return nil
+	}
+	sticky := &stickyWrites{window: stickyWindow, writes: make(map[string]time.Time)}
+	callbacks := db.Callback()
+	return errors.Join(
+		callbacks.Query().Before("gorm:query").Register("app:sticky_primary", sticky.route),
+		callbacks.Row().Before("gorm:row").Register("app:sticky_primary", sticky.route),
+		callbacks.Raw().Before("gorm:raw").Register("app:sticky_primary", sticky.route),
+		callbacks.Create().After("gorm:create").Register("app:sticky_write", sticky.record),
+		callbacks.Update().After("gorm:update").Register("app:sticky_write", sticky.record),
+		callbacks.Delete().After("gorm:delete").Register("app:sticky_write", sticky.record),
+		callbacks.Raw().After("gorm:raw").Register("app:sticky_write", sticky.record),
+	)
+}
+
+// route is a callback that sends a read to the primary, if any of its sticky keys has written recently.
+func (s *stickyWrites) route(db *gorm.DB) {
+	if s.recent(StickyKeysOf(db.Statement.Context)) {
+		dbresolver.Write.ModifyStatement(db.Statement)
+	}
+}
+
+// record is a callback that stores the time of a successful write under all of its sticky keys.
+func (s *stickyWrites) record(db *gorm.DB) {
+	if db.Error != nil {
+		return
+	}
+	s.write(StickyKeysOf(db.Statement.Context))
+}
+
+// recent is a method that reports whether any of the keys has written within the window.
+func (s *stickyWrites) recent(keys []string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range keys {
+		if written, ok := s.writes[key]; ok && time.Since(written) < s.window {
+			return true
+		}
+	}
+	return false
+}
+
+// write is a method that stores the time of a write under every key, expired keys are pruned.
+func (s *stickyWrites) write(keys []string) {
+	if len(keys) == 0 {
+		return
+	}
+
+	now := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range keys {
+		s.writes[key] = now
+	}
+	if now.Sub(s.lastPrune) > s.window {
+		for writeKey, written := range s.writes {
+			if now.Sub(written) >= s.window {
+				delete(s.writes, writeKey)
+			}
+		}
+		s.lastPrune = now
+	}
+}
+
+// StickyKeysOf is a function that returns the sticky keys of the context, nil if there are none.
+func StickyKeysOf(ctx context.Context) []string {
+	if ctx == nil {
+		return nil
+	}
+	keys, _ := ctx.Value(StickyKey).([]string)
+	return keys
+}
+
+// WithStickyKeys is a function that returns a copy of the context with the keys added to its sticky keys.
+func WithStickyKeys(ctx context.Context, keys ...string) context.Context {
+	return context.WithValue(ctx, StickyKey, append(slices.Clone(StickyKeysOf(ctx)), keys...))
+}
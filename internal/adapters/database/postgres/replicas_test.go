@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestStickyWritesAfterRegister(t *testing.T) {
+	sticky := &stickyWrites{window: time.Minute, writes: make(map[string]time.Time)}
+	ctx := context.Background()
+
+	// Register writes before the user id is known, only the IP key is set
+	register := WithStickyKeys(ctx, "ip:203.0.113.1")
+	sticky.write(StickyKeysOf(register))
+
+	// The first authenticated request of the user carries both keys
+	authenticated := WithStickyKeys(WithStickyKeys(ctx, "ip:203.0.113.1"), "user:1")
+	if !sticky.recent(StickyKeysOf(authenticated)) {
+		t.Error("read right after register goes to a replica, want the primary")
+	}
+
+	other := WithStickyKeys(ctx, "ip:198.51.100.7", "user:2")
+	if sticky.recent(StickyKeysOf(other)) {
+		t.Error("read of another client goes to the primary, want a replica")
+	}
+	if sticky.recent(StickyKeysOf(ctx)) {
+		t.Error("read without keys goes to the primary, want a replica")
+	}
+
+	// Writes made with both keys make later requests from other IPs sticky too
+	sticky.write(StickyKeysOf(authenticated))
+	if !sticky.recent(StickyKeysOf(WithStickyKeys(ctx, "ip:198.51.100.8", "user:1"))) {
+		t.Error("read of the user from another IP after its write goes to a replica, want the primary")
+	}
+
+	sticky.writes["ip:203.0.113.1"] = time.Now().Add(-time.Minute)
+	sticky.writes["user:1"] = time.Now().Add(-time.Minute)
+	if sticky.recent(StickyKeysOf(authenticated)) {
+		t.Error("read after the sticky window goes to the primary, want a replica")
+	}
+}
+
+func TestWithStickyKeysCopies(t *testing.T) {
+	base := WithStickyKeys(context.Background(), "ip:203.0.113.1")
+	first := WithStickyKeys(base, "user:1")
+	second := WithStickyKeys(base, "user:2")
+
+	if keys := StickyKeysOf(base); len(keys) != 1 {
+		t.Errorf("base keys = %v, want only the IP", keys)
+	}
+	if keys := StickyKeysOf(first); len(keys) != 2 || keys[1] != "user:1" {
+		t.Errorf("first keys = %v, want the IP and user:1", keys)
+	}
+	if keys := StickyKeysOf(second); len(keys) != 2 || keys[1] != "user:2" {
+		t.Errorf("second keys = %v, want the IP and user:2", keys)
+	}
+}
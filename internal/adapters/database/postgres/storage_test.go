@@ -0,0 +1,31 @@
+package postgres
+
+import (
+	"context"
+	"testing"
+	"webTemplate/internal/adapters/database/storagetest"
+)
+
+func TestStorageContract(t *testing.T) {
+	db := openTestDB(t)
+	if err := Migrate(context.Background(), db); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+
+	// Every test runs in a transaction that is rolled back
+	storagetest.Run(t, func(t *testing.T) (context.Context, storagetest.Storages) {
+		tx := db.Begin()
+		if tx.Error != nil {
+			t.Fatalf("Begin: %v", tx.Error)
+		}
+		t.Cleanup(func() { tx.Rollback() })
+
+		return context.WithValue(context.Background(), txKey{}, tx), storagetest.Storages{
+			Users:       NewUserStorage(db),
+			Tokens:      NewTokenStorage(db),
+			Outbox:      NewOutboxStorage(db),
+			EmailEvents: NewEmailEventStorage(db),
+			Transactor:  NewTransactionManager(db),
+		}
+	})
+}
@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"gorm.io/gorm"
+	"time"
+)
+
+const (
+	// cancelKey is the statement instance key of the cancel func of the query timeout
+	cancelKey = "app:query_timeout_cancel"
+	// noTimeoutKey is the setting that disables the query timeout for a session, see WithoutQueryTimeout
+	noTimeoutKey = "app:no_query_timeout"
+)
+
+// RegisterQueryTimeout is a function that adds gorm callbacks bounding every query whose context has no deadline.
+// Callers can still set a longer or shorter deadline on the context.
+/*
+ * timeout time.Duration - the default query timeout, 0 disables it
+ */
+func RegisterQueryTimeout(db *gorm.DB, timeout time.Duration) error {
+	if timeout <= 0 {
+		return nil
+	}
+
+	callbacks := db.Callback()
+	start := startTimeout(timeout)
+	return errors.Join(
+		callbacks.Query().Before("gorm:query").Register("app:query_timeout", start),
+		callbacks.Query().After("gorm:query").Register("app:query_timeout_cancel", stopTimeout),
+		callbacks.Create().Before("gorm:create").Register("app:query_timeout", start),
+		callbacks.Create().After("gorm:create").Register("app:query_timeout_cancel", stopTimeout),
+		callbacks.Update().Before("gorm:update").Register("app:query_timeout", start),
+		callbacks.Update().After("gorm:update").Register("app:query_timeout_cancel", stopTimeout),
+		callbacks.Delete().Before("gorm:delete").Register("app:query_timeout", start),
+		callbacks.Delete().After("gorm:delete").Register("app:query_timeout_cancel", stopTimeout),
+		callbacks.Raw().Before("gorm:raw").Register("app:query_timeout", start),
+		callbacks.Raw().After("gorm:raw").Register("app:query_timeout_cancel", stopTimeout),
+		// Rows of db.Rows and db.Row are read after the callbacks, their context is released when the timeout expires
+		callbacks.Row().Before("gorm:row").Register("app:query_timeout", start),
+	)
+}
+
+// WithoutQueryTimeout is a function that returns a session without the default query timeout,
+// e.g. for migrations or waiting for a lock.
+func WithoutQueryTimeout(db *gorm.DB) *gorm.DB {
+	return db.Set(noTimeoutKey, true)
+}
+
+// startTimeout is a function that returns a callback replacing the statement context by one with the timeout.
+func startTimeout(timeout time.Duration) func(db *gorm.DB) {
+	return func(db *gorm.DB) {
+		if _, disabled := db.Get(noTimeoutKey); disabled {
+			return
+		}
+		if _, hasDeadline := db.Statement.Context.Deadline(); hasDeadline {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(db.Statement.Context, timeout)
+		db.Statement.Context = ctx
+		db.InstanceSet(cancelKey, cancel)
+	}
+}
+
+// stopTimeout is a callback that releases the timeout context of a finished statement.
+func stopTimeout(db *gorm.DB) {
+	if cancel, ok := db.InstanceGet(cancelKey); ok {
+		cancel.(context.CancelFunc)()
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"gorm.io/gorm"
 	"time"
+	"webTemplate/internal/domain/common/errorz"
 	"webTemplate/internal/domain/entity"
 )
 
@@ -19,25 +20,36 @@ func NewTokenStorage(db *gorm.DB) *tokenStorage {
 
 // Create is a method to create a new Token in database.
 func (s *tokenStorage) Create(ctx context.Context, token entity.Token) (*entity.Token, error) {
-	err := s.db.WithContext(ctx).Create(&token).Error
-	return &token, err
+	err := contextDB(ctx, s.db).Create(&token).Error
+	return &token, storageError(err, errorz.TokenNotFound)
 }
 
 // GetByUserID is a method that returns an error and a pointer to a Token instance by user id and token type.
 func (s *tokenStorage) GetByUserID(ctx context.Context, userID string, tokenType string) (*entity.Token, error) {
 	var token *entity.Token
-	err := s.db.WithContext(ctx).Model(&entity.Token{}).Where(
-		"id = ? AND type = ? AND expires > ?", userID, tokenType, time.Now(),
+	err := contextDB(ctx, s.db).Model(&entity.Token{}).Where(
+		"user_id = ? AND type = ? AND expires > ?", userID, tokenType, time.Now(),
 	).First(&token).Error
-	return token, err
+	return token, storageError(err, errorz.TokenNotFound)
+}
+
+// GetByToken is a method that returns an error and a pointer to a not expired Token instance by token string and token type.
+func (s *tokenStorage) GetByToken(ctx context.Context, token string, tokenType string) (*entity.Token, error) {
+	var result *entity.Token
+	err := contextDB(ctx, s.db).Model(&entity.Token{}).Where(
+		"token = ? AND type = ? AND expires > ?", token, tokenType, time.Now(),
+	).First(&result).Error
+	return result, storageError(err, errorz.TokenNotFound)
 }
 
 // DeleteAll is a method to delete all user Tokens in database.
 func (s *tokenStorage) DeleteAll(ctx context.Context, userID string) error {
-	return s.db.WithContext(ctx).Delete(&entity.Token{}, "user_id = ?", userID).Error
+	err := contextDB(ctx, s.db).Delete(&entity.Token{}, "user_id = ?", userID).Error
+	return storageError(err, errorz.TokenNotFound)
 }
 
 // Delete is a method to delete an existing Token in database by user id and token type.
 func (s *tokenStorage) Delete(ctx context.Context, userID string, tokenType string) error {
-	return s.db.WithContext(ctx).Delete(&entity.Token{}, "user_id = ? AND type = ?", userID, tokenType).Error
+	err := contextDB(ctx, s.db).Delete(&entity.Token{}, "user_id = ? AND type = ?", userID, tokenType).Error
+	return storageError(err, errorz.TokenNotFound)
 }
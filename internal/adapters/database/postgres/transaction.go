@@ -0,0 +1,36 @@
+package postgres
+
+import (
+	"context"
+	"gorm.io/gorm"
+)
+
+type txKey struct{}
+
+// transactionManager is a struct that runs functions in database transactions shared by all storages.
+type transactionManager struct {
+	db *gorm.DB
+}
+
+// NewTransactionManager is a function that returns a new instance of transactionManager.
+func NewTransactionManager(db *gorm.DB) *transactionManager {
+	return &transactionManager{db: db}
+}
+
+// Do is a method that runs fn in a transaction, committed if fn returns nil and rolled back otherwise.
+// Storages called with the context passed to fn use the transaction, so users, tokens and outbox emails
+// can be changed atomically. Nested calls run in a savepoint of the outer transaction.
+func (m *transactionManager) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return contextDB(ctx, m.db).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// contextDB is a function that returns the transaction started by transactionManager.Do for the context,
+// or db if there is none, bound to the context.
+func contextDB(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
@@ -6,6 +6,7 @@ import (
 	"gorm.io/gorm"
 	"webTemplate/internal/domain/common/errorz"
 	"webTemplate/internal/domain/entity"
+	"webTemplate/internal/domain/utils/username"
 )
 
 // userStorage is a struct that contains a pointer to a gorm.DB instance to interact with user repository.
@@ -20,41 +21,55 @@ func NewUserStorage(db *gorm.DB) *userStorage {
 
 // Create is a method to create a new User in database.
 func (s *userStorage) Create(ctx context.Context, user entity.User) (*entity.User, error) {
-	if err := s.db.WithContext(ctx).Where("email = ? AND verified_email = true", user.Email).Error; !errors.Is(err, gorm.ErrRecordNotFound) {
+	err := contextDB(ctx, s.db).Where(
+		"email_canonical = ? AND verified_email = true", username.CanonicalEmail(user.Email),
+	).First(&entity.User{}).Error
+	if err == nil {
 		return nil, errorz.EmailAlreadyTaken
 	}
-	err := s.db.WithContext(ctx).Create(&user).Error
-	return &user, err
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, storageError(err, errorz.UserNotFound)
+	}
+	err = contextDB(ctx, s.db).Create(&user).Error
+	return &user, storageError(err, errorz.UserNotFound)
 }
 
 // GetByID is a method that returns an error and a pointer to a User instance by id.
 func (s *userStorage) GetByID(ctx context.Context, id string) (*entity.User, error) {
 	var user *entity.User
-	err := s.db.WithContext(ctx).Model(&entity.User{}).Where("id = ?", id).First(&user).Error
-	return user, err
+	err := contextDB(ctx, s.db).Model(&entity.User{}).Where("id = ?", id).First(&user).Error
+	return user, storageError(err, errorz.UserNotFound)
 }
 
-// GetAll is a method that returns a slice of pointers to all User instances.
+// GetAll is a method that returns a page of users in the order of creation.
 func (s *userStorage) GetAll(ctx context.Context, limit, offset int) ([]entity.User, error) {
 	var users []entity.User
-	err := s.db.WithContext(ctx).Model(&entity.User{}).Limit(limit).Offset(offset).Find(&users).Error
-	return users, err
+	err := contextDB(ctx, s.db).Model(&entity.User{}).Order("created_at, id").Limit(limit).Offset(offset).Find(&users).Error
+	return users, storageError(err, errorz.UserNotFound)
 }
 
 // Update is a method to update an existing User in database.
 func (s *userStorage) Update(ctx context.Context, user *entity.User) (*entity.User, error) {
-	err := s.db.WithContext(ctx).Model(&entity.User{}).Where("id = ?", user.ID).Updates(&user).Error
-	return user, err
+	err := contextDB(ctx, s.db).Model(&entity.User{}).Where("id = ?", user.ID).Updates(&user).Error
+	return user, storageError(err, errorz.UserNotFound)
 }
 
 // Delete is a method to delete an existing User in database.
 func (s *userStorage) Delete(ctx context.Context, id string) error {
-	return s.db.WithContext(ctx).Unscoped().Delete(&entity.User{}, "id = ?", id).Error
+	err := contextDB(ctx, s.db).Unscoped().Delete(&entity.User{}, "id = ?", id).Error
+	return storageError(err, errorz.UserNotFound)
 }
 
-// GetByEmail is a method that returns a pointer to a User instance and error by email.
+// GetByEmail is a method that returns a pointer to a User instance and error by case-insensitive email.
 func (s *userStorage) GetByEmail(ctx context.Context, email string) (*entity.User, error) {
 	var user *entity.User
-	err := s.db.WithContext(ctx).Where("email = ?", email).First(&user).Error
-	return user, err
+	err := contextDB(ctx, s.db).Where("email_canonical = ?", username.CanonicalEmail(email)).First(&user).Error
+	return user, storageError(err, errorz.UserNotFound)
+}
+
+// GetByUsername is a method that returns a pointer to a User instance and error by canonical username.
+func (s *userStorage) GetByUsername(ctx context.Context, name string) (*entity.User, error) {
+	var user *entity.User
+	err := contextDB(ctx, s.db).Where("username_canonical = ?", username.Canonical(name)).First(&user).Error
+	return user, storageError(err, errorz.UserNotFound)
 }
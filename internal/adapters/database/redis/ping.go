@@ -0,0 +1,43 @@
+package redis
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// Ping is a function that checks that a redis server answers the PING command.
+// A server requiring authentication is reachable too, so "-NOAUTH" counts as an answer.
+/*
+ * address string - host:port of the server
+ */
+func Ping(ctx context.Context, address string) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err = conn.SetDeadline(deadline); err != nil {
+			return err
+		}
+	}
+
+	if _, err = conn.Write([]byte("PING\r\n")); err != nil {
+		return err
+	}
+	reply, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil {
+		return err
+	}
+
+	reply = strings.TrimSpace(reply)
+	if reply != "+PONG" && !strings.HasPrefix(reply, "-NOAUTH") {
+		return fmt.Errorf("unexpected reply to PING: %q", reply)
+	}
+	return nil
+}
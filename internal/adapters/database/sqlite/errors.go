@@ -0,0 +1,25 @@
+package sqlite
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"webTemplate/internal/domain/common/errorz"
+)
+
+// storageError is a function that converts gorm errors to domain errors, so storage details don't leak to clients.
+/*
+ * err error - error returned by gorm
+ * notFound *errorz.Error - domain error returned when no record was found
+ */
+func storageError(err error, notFound *errorz.Error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return notFound.Wrap(err)
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return errorz.Wrap(err, errorz.CodeConflict, "already exists")
+	default:
+		return errorz.Internal(err)
+	}
+}
@@ -0,0 +1,82 @@
+package sqlite
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+	"webTemplate/internal/domain/common/errorz"
+	"webTemplate/internal/domain/entity"
+)
+
+// outboxStorage is a struct that contains a pointer to a sqlite gorm.DB instance to interact with email outbox repository.
+type outboxStorage struct {
+	db *gorm.DB
+}
+
+// NewOutboxStorage is a function that returns a new instance of outboxStorage.
+func NewOutboxStorage(db *gorm.DB) *outboxStorage {
+	return &outboxStorage{db: db}
+}
+
+// Create is a method to create a new OutboxEmail in database, sqlite has no uuid default, so the id is generated here.
+func (s *outboxStorage) Create(ctx context.Context, email entity.OutboxEmail) (*entity.OutboxEmail, error) {
+	if email.ID == "" {
+		email.ID = uuid.NewString()
+	}
+	// Times are compared as text, they must share the time zone
+	email.NextAttemptAt = email.NextAttemptAt.UTC()
+	err := contextDB(ctx, s.db).Create(&email).Error
+	return &email, storageError(err, errorz.NotFound)
+}
+
+// Claim is a method that locks up to limit due OutboxEmail instances for the lease duration and returns them.
+// Sqlite has a single writer, so the rows selected in the transaction can't be claimed by another worker.
+func (s *outboxStorage) Claim(ctx context.Context, limit int, lease time.Duration) ([]entity.OutboxEmail, error) {
+	var emails []entity.OutboxEmail
+	now := time.Now().UTC()
+	err := contextDB(ctx, s.db).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where(
+			"sent_at IS NULL AND failed_at IS NULL AND next_attempt_at <= ?", now,
+		).Order("next_attempt_at").Limit(limit).Find(&emails).Error
+		if err != nil || len(emails) == 0 {
+			return err
+		}
+
+		ids := make([]string, len(emails))
+		for i := range emails {
+			ids[i] = emails[i].ID
+			emails[i].NextAttemptAt = now.Add(lease)
+			emails[i].UpdatedAt = now
+		}
+		return tx.Model(&entity.OutboxEmail{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+			"next_attempt_at": now.Add(lease),
+			"updated_at":      now,
+		}).Error
+	})
+	return emails, storageError(err, errorz.NotFound)
+}
+
+// MarkSent is a method to mark an OutboxEmail as successfully sent.
+func (s *outboxStorage) MarkSent(ctx context.Context, id string) error {
+	err := contextDB(ctx, s.db).Model(&entity.OutboxEmail{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"sent_at":  time.Now().UTC(),
+		"attempts": gorm.Expr("attempts + 1"),
+	}).Error
+	return storageError(err, errorz.NotFound)
+}
+
+// MarkFailed is a method to record a failed attempt, nil nextAttemptAt marks the OutboxEmail as failed permanently.
+func (s *outboxStorage) MarkFailed(ctx context.Context, id string, lastError string, nextAttemptAt *time.Time) error {
+	updates := map[string]interface{}{
+		"last_error": lastError,
+		"attempts":   gorm.Expr("attempts + 1"),
+	}
+	if nextAttemptAt != nil {
+		updates["next_attempt_at"] = nextAttemptAt.UTC()
+	} else {
+		updates["failed_at"] = time.Now().UTC()
+	}
+	err := contextDB(ctx, s.db).Model(&entity.OutboxEmail{}).Where("id = ?", id).Updates(updates).Error
+	return storageError(err, errorz.NotFound)
+}
@@ -0,0 +1,67 @@
+-- Tables of the postgres migrations, ids are generated by the storages.
+
+CREATE TABLE IF NOT EXISTS "users" (
+    "id"                        text     NOT NULL,
+    "created_at"                datetime,
+    "updated_at"                datetime,
+    "email"                     text,
+    "verified_email"            boolean  NOT NULL DEFAULT false,
+    "verification_code"         text     NOT NULL DEFAULT 'NULL',
+    "password"                  blob,
+    "role"                      text     NOT NULL DEFAULT 'user',
+    "username"                  text,
+    "email_canonical"           text,
+    "username_canonical"        text,
+    "email_delivery_state"      text     NOT NULL DEFAULT 'unknown',
+    "email_delivery_updated_at" datetime,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_users_email" ON "users" ("email");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_email_canonical" ON "users" ("email_canonical");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_users_username_canonical" ON "users" ("username_canonical");
+
+CREATE TABLE IF NOT EXISTS "tokens" (
+    "id"         text     NOT NULL,
+    "created_at" datetime,
+    "updated_at" datetime,
+    "token"      text     NOT NULL,
+    "user_id"    text     NOT NULL,
+    "type"       text     NOT NULL,
+    "expires"    datetime NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_users_token" FOREIGN KEY ("user_id") REFERENCES "users" ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_tokens_token" ON "tokens" ("token");
+
+CREATE TABLE IF NOT EXISTS "email_events" (
+    "id"          text     NOT NULL,
+    "created_at"  datetime,
+    "user_id"     text,
+    "email"       text     NOT NULL,
+    "type"        text     NOT NULL,
+    "message_id"  text,
+    "reason"      text,
+    "occurred_at" datetime NOT NULL,
+    PRIMARY KEY ("id"),
+    CONSTRAINT "fk_email_events_user" FOREIGN KEY ("user_id") REFERENCES "users" ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_email_events_user_id" ON "email_events" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_email_events_email" ON "email_events" ("email");
+CREATE INDEX IF NOT EXISTS "idx_email_events_message_id" ON "email_events" ("message_id");
+
+CREATE TABLE IF NOT EXISTS "outbox_emails" (
+    "id"              text     NOT NULL,
+    "created_at"      datetime,
+    "updated_at"      datetime,
+    "email"           text     NOT NULL,
+    "subject"         text     NOT NULL,
+    "body"            text     NOT NULL,
+    "attempts"        integer  NOT NULL DEFAULT 0,
+    "next_attempt_at" datetime NOT NULL,
+    "sent_at"         datetime,
+    "failed_at"       datetime,
+    "last_error"      text,
+    "request_id"      text,
+    PRIMARY KEY ("id")
+);
+CREATE INDEX IF NOT EXISTS "idx_outbox_emails_next_attempt_at" ON "outbox_emails" ("next_attempt_at");
@@ -0,0 +1,52 @@
+package sqlite
+
+import (
+	_ "embed"
+	"fmt"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// MemoryPath is the path of a private in-memory database, it is lost when closed.
+const MemoryPath = ":memory:"
+
+//go:embed schema.sql
+var schema string
+
+// Open is a function that opens a sqlite database file and creates the tables of the storages in it.
+// Foreign keys are enforced and times are stored in UTC, so they are compared correctly as text.
+/*
+ * path string - path of the database file or MemoryPath
+ * gormConfig *gorm.Config - gorm settings, e.g. the logger, TranslateError and NowFunc are always set
+ */
+func Open(path string, gormConfig *gorm.Config) (*gorm.DB, error) {
+	if path != MemoryPath {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create sqlite database directory: %w", err)
+		}
+	}
+
+	gormConfig.TranslateError = true
+	gormConfig.NowFunc = func() time.Time { return time.Now().UTC() }
+
+	db, err := gorm.Open(sqlite.Open(path+"?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_time_format=sqlite"), gormConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sqlite database %s: %w", path, err)
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sqlite pool: %w", err)
+	}
+	// Every connection to ":memory:" opens its own database, sqlite serializes writes anyway
+	sqlDB.SetMaxOpenConns(1)
+
+	if err = db.Exec(schema).Error; err != nil {
+		_ = sqlDB.Close()
+		return nil, fmt.Errorf("failed to create sqlite schema: %w", err)
+	}
+	return db, nil
+}
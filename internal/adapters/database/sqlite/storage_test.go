@@ -0,0 +1,31 @@
+package sqlite
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"testing"
+	"webTemplate/internal/adapters/database/storagetest"
+)
+
+func TestStorageContract(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) (context.Context, storagetest.Storages) {
+		db, err := Open(MemoryPath, &gorm.Config{Logger: logger.Discard})
+		if err != nil {
+			t.Fatalf("Open: %v", err)
+		}
+		t.Cleanup(func() {
+			if sqlDB, err := db.DB(); err == nil {
+				_ = sqlDB.Close()
+			}
+		})
+
+		return context.Background(), storagetest.Storages{
+			Users:       NewUserStorage(db),
+			Tokens:      NewTokenStorage(db),
+			Outbox:      NewOutboxStorage(db),
+			EmailEvents: NewEmailEventStorage(db),
+			Transactor:  NewTransactionManager(db),
+		}
+	})
+}
@@ -0,0 +1,61 @@
+package sqlite
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"time"
+	"webTemplate/internal/domain/common/errorz"
+	"webTemplate/internal/domain/entity"
+)
+
+// tokenStorage is a struct that contains a pointer to a sqlite gorm.DB instance to interact with token repository.
+type tokenStorage struct {
+	db *gorm.DB
+}
+
+// NewTokenStorage is a function that returns a new instance of tokenStorage.
+func NewTokenStorage(db *gorm.DB) *tokenStorage {
+	return &tokenStorage{db: db}
+}
+
+// Create is a method to create a new Token in database, sqlite has no uuid default, so the id is generated here.
+func (s *tokenStorage) Create(ctx context.Context, token entity.Token) (*entity.Token, error) {
+	if token.ID == "" {
+		token.ID = uuid.NewString()
+	}
+	// Times are compared as text, they must share the time zone
+	token.Expires = token.Expires.UTC()
+	err := contextDB(ctx, s.db).Create(&token).Error
+	return &token, storageError(err, errorz.TokenNotFound)
+}
+
+// GetByUserID is a method that returns an error and a pointer to a not expired Token instance by user id and token type.
+func (s *tokenStorage) GetByUserID(ctx context.Context, userID string, tokenType string) (*entity.Token, error) {
+	var token *entity.Token
+	err := contextDB(ctx, s.db).Model(&entity.Token{}).Where(
+		"user_id = ? AND type = ? AND expires > ?", userID, tokenType, time.Now().UTC(),
+	).First(&token).Error
+	return token, storageError(err, errorz.TokenNotFound)
+}
+
+// GetByToken is a method that returns an error and a pointer to a not expired Token instance by token string and token type.
+func (s *tokenStorage) GetByToken(ctx context.Context, token string, tokenType string) (*entity.Token, error) {
+	var result *entity.Token
+	err := contextDB(ctx, s.db).Model(&entity.Token{}).Where(
+		"token = ? AND type = ? AND expires > ?", token, tokenType, time.Now().UTC(),
+	).First(&result).Error
+	return result, storageError(err, errorz.TokenNotFound)
+}
+
+// DeleteAll is a method to delete all user Tokens in database.
+func (s *tokenStorage) DeleteAll(ctx context.Context, userID string) error {
+	err := contextDB(ctx, s.db).Delete(&entity.Token{}, "user_id = ?", userID).Error
+	return storageError(err, errorz.TokenNotFound)
+}
+
+// Delete is a method to delete an existing Token in database by user id and token type.
+func (s *tokenStorage) Delete(ctx context.Context, userID string, tokenType string) error {
+	err := contextDB(ctx, s.db).Delete(&entity.Token{}, "user_id = ? AND type = ?", userID, tokenType).Error
+	return storageError(err, errorz.TokenNotFound)
+}
@@ -0,0 +1,79 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"webTemplate/internal/domain/common/errorz"
+	"webTemplate/internal/domain/entity"
+	"webTemplate/internal/domain/utils/username"
+)
+
+// userStorage is a struct that contains a pointer to a sqlite gorm.DB instance to interact with user repository.
+type userStorage struct {
+	db *gorm.DB
+}
+
+// NewUserStorage is a function that returns a new instance of userStorage.
+func NewUserStorage(db *gorm.DB) *userStorage {
+	return &userStorage{db: db}
+}
+
+// Create is a method to create a new User in database, sqlite has no uuid default, so the id is generated here.
+func (s *userStorage) Create(ctx context.Context, user entity.User) (*entity.User, error) {
+	err := contextDB(ctx, s.db).Where(
+		"email_canonical = ? AND verified_email = true", username.CanonicalEmail(user.Email),
+	).First(&entity.User{}).Error
+	if err == nil {
+		return nil, errorz.EmailAlreadyTaken
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, storageError(err, errorz.UserNotFound)
+	}
+	if user.ID == "" {
+		user.ID = uuid.NewString()
+	}
+	err = contextDB(ctx, s.db).Create(&user).Error
+	return &user, storageError(err, errorz.UserNotFound)
+}
+
+// GetByID is a method that returns an error and a pointer to a User instance by id.
+func (s *userStorage) GetByID(ctx context.Context, id string) (*entity.User, error) {
+	var user *entity.User
+	err := contextDB(ctx, s.db).Model(&entity.User{}).Where("id = ?", id).First(&user).Error
+	return user, storageError(err, errorz.UserNotFound)
+}
+
+// GetAll is a method that returns a page of users in the order of creation.
+func (s *userStorage) GetAll(ctx context.Context, limit, offset int) ([]entity.User, error) {
+	var users []entity.User
+	err := contextDB(ctx, s.db).Model(&entity.User{}).Order("created_at, id").Limit(limit).Offset(offset).Find(&users).Error
+	return users, storageError(err, errorz.UserNotFound)
+}
+
+// Update is a method to update an existing User in database.
+func (s *userStorage) Update(ctx context.Context, user *entity.User) (*entity.User, error) {
+	err := contextDB(ctx, s.db).Model(&entity.User{}).Where("id = ?", user.ID).Updates(&user).Error
+	return user, storageError(err, errorz.UserNotFound)
+}
+
+// Delete is a method to delete an existing User in database.
+func (s *userStorage) Delete(ctx context.Context, id string) error {
+	err := contextDB(ctx, s.db).Delete(&entity.User{}, "id = ?", id).Error
+	return storageError(err, errorz.UserNotFound)
+}
+
+// GetByEmail is a method that returns a pointer to a User instance and error by case-insensitive email.
+func (s *userStorage) GetByEmail(ctx context.Context, email string) (*entity.User, error) {
+	var user *entity.User
+	err := contextDB(ctx, s.db).Where("email_canonical = ?", username.CanonicalEmail(email)).First(&user).Error
+	return user, storageError(err, errorz.UserNotFound)
+}
+
+// GetByUsername is a method that returns a pointer to a User instance and error by canonical username.
+func (s *userStorage) GetByUsername(ctx context.Context, name string) (*entity.User, error) {
+	var user *entity.User
+	err := contextDB(ctx, s.db).Where("username_canonical = ?", username.Canonical(name)).First(&user).Error
+	return user, storageError(err, errorz.UserNotFound)
+}
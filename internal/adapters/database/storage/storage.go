@@ -0,0 +1,119 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"gorm.io/gorm"
+	"time"
+	"webTemplate/internal/adapters/config"
+	"webTemplate/internal/adapters/database/memory"
+	"webTemplate/internal/adapters/database/postgres"
+	"webTemplate/internal/adapters/database/sqlite"
+	"webTemplate/internal/adapters/logger"
+	"webTemplate/internal/domain/service"
+	authUsecase "webTemplate/internal/domain/usecase/auth"
+)
+
+const (
+	Postgres = "postgres"
+	SQLite   = "sqlite"
+	Memory   = "memory"
+)
+
+// Storages is a struct that contains the storages selected by service.database.storage.
+// Every part of the app must share them, the memory storages keep their data only in the instance.
+type Storages struct {
+	Users       service.UserStorage
+	Tokens      service.TokenStorage
+	Outbox      service.OutboxStorage
+	EmailEvents service.EmailEventStorage
+	// Transactor runs functions in transactions covering all of the storages above
+	Transactor authUsecase.Transactor
+	// ping checks that the storages are available, nil if they always are
+	ping func(ctx context.Context) error
+	// close releases the resources of the storages, e.g. the sqlite database
+	close func() error
+}
+
+// New is a function that creates the storages of service.database.storage.
+/*
+ * dbConfig config.DatabaseConfig - database settings, Storage selects the implementation
+ * db *gorm.DB - postgres connection, used by the postgres storages and as the source of the sqlite logger, may be nil for other storages
+ */
+func New(dbConfig config.DatabaseConfig, db *gorm.DB) (*Storages, error) {
+	switch dbConfig.Storage {
+	case "", Postgres:
+		return &Storages{
+			Users:       postgres.NewUserStorage(db),
+			Tokens:      postgres.NewTokenStorage(db),
+			Outbox:      postgres.NewOutboxStorage(db),
+			EmailEvents: postgres.NewEmailEventStorage(db),
+			Transactor:  postgres.NewTransactionManager(db),
+			ping:        func(ctx context.Context) error { return postgres.Ping(ctx, db) },
+		}, nil
+	case SQLite:
+		gormConfig := &gorm.Config{Logger: logger.Gorm(time.Second, false)}
+		if db != nil {
+			gormConfig.Logger = db.Logger
+		}
+		sqliteDB, err := sqlite.Open(dbConfig.SQLitePath, gormConfig)
+		if err != nil {
+			return nil, err
+		}
+		sqlDB, err := sqliteDB.DB()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get sqlite pool: %w", err)
+		}
+		logger.Log.Warnf("Data is stored in sqlite database %s", dbConfig.SQLitePath)
+		return &Storages{
+			Users:       sqlite.NewUserStorage(sqliteDB),
+			Tokens:      sqlite.NewTokenStorage(sqliteDB),
+			Outbox:      sqlite.NewOutboxStorage(sqliteDB),
+			EmailEvents: sqlite.NewEmailEventStorage(sqliteDB),
+			Transactor:  sqlite.NewTransactionManager(sqliteDB),
+			ping:        sqlDB.PingContext,
+			close:       sqlDB.Close,
+		}, nil
+	case Memory:
+		logger.Log.Warn("Data is stored in memory, it is lost on restart")
+		return NewMemory(), nil
+	default:
+		return nil, fmt.Errorf("unknown storage %q", dbConfig.Storage)
+	}
+}
+
+// NewMemory is a function that creates empty memory storages.
+func NewMemory() *Storages {
+	users := memory.NewUserStorage()
+	tokens := memory.NewTokenStorage()
+	outbox := memory.NewOutboxStorage()
+	emailEvents := memory.NewEmailEventStorage()
+	return &Storages{
+		Users:       users,
+		Tokens:      tokens,
+		Outbox:      outbox,
+		EmailEvents: emailEvents,
+		Transactor:  memory.NewTransactionManager(users, tokens, outbox, emailEvents),
+	}
+}
+
+// UsesPostgres is a function that reports whether the storages of the config need the postgres database.
+func UsesPostgres(dbConfig config.DatabaseConfig) bool {
+	return dbConfig.Storage == "" || dbConfig.Storage == Postgres
+}
+
+// Ping is a method that checks that the storages are available.
+func (s *Storages) Ping(ctx context.Context) error {
+	if s.ping == nil {
+		return nil
+	}
+	return s.ping(ctx)
+}
+
+// Close is a method that releases the resources of the storages.
+func (s *Storages) Close() error {
+	if s.close == nil {
+		return nil
+	}
+	return s.close()
+}
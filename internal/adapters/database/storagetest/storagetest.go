@@ -0,0 +1,547 @@
+// Package storagetest is the repository contract every storage backend must pass,
+// so the memory and sqlite storages can stand in for postgres in tests and local development.
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"github.com/google/uuid"
+	"testing"
+	"time"
+	"webTemplate/internal/domain/common/errorz"
+	"webTemplate/internal/domain/entity"
+	"webTemplate/internal/domain/utils/username"
+)
+
+// UserStorage is the user repository under test, the same as service.UserStorage.
+// It is repeated here, so the postgres package can test itself without an import cycle.
+type UserStorage interface {
+	Create(ctx context.Context, user entity.User) (*entity.User, error)
+	GetByID(ctx context.Context, id string) (*entity.User, error)
+	GetAll(ctx context.Context, limit, offset int) ([]entity.User, error)
+	Update(ctx context.Context, user *entity.User) (*entity.User, error)
+	Delete(ctx context.Context, id string) error
+	GetByEmail(ctx context.Context, email string) (*entity.User, error)
+	GetByUsername(ctx context.Context, username string) (*entity.User, error)
+}
+
+// TokenStorage is the token repository under test, the same as service.TokenStorage.
+type TokenStorage interface {
+	Create(ctx context.Context, token entity.Token) (*entity.Token, error)
+	GetByUserID(ctx context.Context, userID string, tokenType string) (*entity.Token, error)
+	GetByToken(ctx context.Context, token string, tokenType string) (*entity.Token, error)
+	DeleteAll(ctx context.Context, userID string) error
+	Delete(ctx context.Context, userID string, tokenType string) error
+}
+
+// OutboxStorage is the email outbox repository under test, the same as service.OutboxStorage.
+type OutboxStorage interface {
+	Create(ctx context.Context, email entity.OutboxEmail) (*entity.OutboxEmail, error)
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]entity.OutboxEmail, error)
+	MarkSent(ctx context.Context, id string) error
+	MarkFailed(ctx context.Context, id string, lastError string, nextAttemptAt *time.Time) error
+}
+
+// EmailEventStorage is the email event repository under test, the same as service.EmailEventStorage.
+type EmailEventStorage interface {
+	Create(ctx context.Context, event entity.EmailEvent) (*entity.EmailEvent, error)
+	GetByUserID(ctx context.Context, userID string, limit int) ([]entity.EmailEvent, error)
+}
+
+// Transactor runs functions in transactions of the storages under test, the same as auth.Transactor.
+type Transactor interface {
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// Storages is a struct of the storages under test, tokens are created for users created through Users.
+// Transactor must cover all of them.
+type Storages struct {
+	Users       UserStorage
+	Tokens      TokenStorage
+	Outbox      OutboxStorage
+	EmailEvents EmailEventStorage
+	Transactor  Transactor
+}
+
+// Open is a function that returns storages isolated from other tests, with the context to call them with.
+type Open func(t *testing.T) (context.Context, Storages)
+
+// Run is a function that runs the contract tests against the storages returned by open for every test.
+func Run(t *testing.T, open Open) {
+	t.Run("users", func(t *testing.T) { runUsers(t, open) })
+	t.Run("tokens", func(t *testing.T) { runTokens(t, open) })
+	t.Run("outbox", func(t *testing.T) { runOutbox(t, open) })
+	t.Run("email events", func(t *testing.T) { runEmailEvents(t, open) })
+	t.Run("transactions", func(t *testing.T) { runTransactions(t, open) })
+}
+
+func runUsers(t *testing.T, open Open) {
+	t.Run("create fills id and defaults", func(t *testing.T) {
+		ctx, storages := open(t)
+		user := mustCreateUser(t, ctx, storages, newUser("alice"))
+
+		if _, err := uuid.Parse(user.ID); err != nil {
+			t.Errorf("id %q is not a uuid: %v", user.ID, err)
+		}
+		if user.CreatedAt.IsZero() {
+			t.Error("created_at is not set")
+		}
+		if user.Role != entity.RoleUser {
+			t.Errorf("role = %q, want %q", user.Role, entity.RoleUser)
+		}
+		if user.EmailDeliveryState != entity.EmailDeliveryUnknown {
+			t.Errorf("email_delivery_state = %q, want %q", user.EmailDeliveryState, entity.EmailDeliveryUnknown)
+		}
+
+		found, err := storages.Users.GetByID(ctx, user.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if found.Email != user.Email || found.Username != user.Username || string(found.Password) != string(user.Password) {
+			t.Errorf("GetByID = %+v, want %+v", found, user)
+		}
+	})
+
+	t.Run("missing user is not found", func(t *testing.T) {
+		ctx, storages := open(t)
+		_, err := storages.Users.GetByID(ctx, uuid.NewString())
+		wantError(t, err, errorz.UserNotFound)
+		_, err = storages.Users.GetByEmail(ctx, "nobody@example.com")
+		wantError(t, err, errorz.UserNotFound)
+		_, err = storages.Users.GetByUsername(ctx, "nobody")
+		wantError(t, err, errorz.UserNotFound)
+	})
+
+	t.Run("lookups are case-insensitive", func(t *testing.T) {
+		ctx, storages := open(t)
+		user := mustCreateUser(t, ctx, storages, newUser("Bob"))
+
+		byEmail, err := storages.Users.GetByEmail(ctx, "BOB@example.COM")
+		if err != nil || byEmail.ID != user.ID {
+			t.Errorf("GetByEmail = %v, %v, want user %s", byEmail, err, user.ID)
+		}
+		byUsername, err := storages.Users.GetByUsername(ctx, "BOB")
+		if err != nil || byUsername.ID != user.ID {
+			t.Errorf("GetByUsername = %v, %v, want user %s", byUsername, err, user.ID)
+		}
+	})
+
+	t.Run("verified email is taken", func(t *testing.T) {
+		ctx, storages := open(t)
+		verified := newUser("carol")
+		verified.VerifiedEmail = true
+		mustCreateUser(t, ctx, storages, verified)
+
+		duplicate := newUser("carol2")
+		duplicate.Email = "Carol@example.com"
+		duplicate.EmailCanonical = username.CanonicalEmail(duplicate.Email)
+		_, err := storages.Users.Create(ctx, duplicate)
+		wantError(t, err, errorz.EmailAlreadyTaken)
+	})
+
+	t.Run("duplicate username conflicts", func(t *testing.T) {
+		ctx, storages := open(t)
+		mustCreateUser(t, ctx, storages, newUser("dave"))
+
+		duplicate := newUser("dave")
+		duplicate.Email = "other-dave@example.com"
+		duplicate.EmailCanonical = username.CanonicalEmail(duplicate.Email)
+		_, err := storages.Users.Create(ctx, duplicate)
+		wantCode(t, err, errorz.CodeConflict)
+	})
+
+	t.Run("update changes only set fields", func(t *testing.T) {
+		ctx, storages := open(t)
+		user := mustCreateUser(t, ctx, storages, newUser("erin"))
+
+		if _, err := storages.Users.Update(ctx, &entity.User{ID: user.ID, Role: entity.RoleAdmin, VerifiedEmail: true}); err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+
+		found, err := storages.Users.GetByID(ctx, user.ID)
+		if err != nil {
+			t.Fatalf("GetByID: %v", err)
+		}
+		if found.Role != entity.RoleAdmin || !found.VerifiedEmail {
+			t.Errorf("role = %q, verified = %t, want %q, true", found.Role, found.VerifiedEmail, entity.RoleAdmin)
+		}
+		if found.Email != user.Email || found.Username != user.Username {
+			t.Errorf("email = %q, username = %q, want them unchanged", found.Email, found.Username)
+		}
+	})
+
+	t.Run("update to a taken username conflicts", func(t *testing.T) {
+		ctx, storages := open(t)
+		mustCreateUser(t, ctx, storages, newUser("frank"))
+		user := mustCreateUser(t, ctx, storages, newUser("grace"))
+
+		_, err := storages.Users.Update(ctx, &entity.User{ID: user.ID, Username: "frank", UsernameCanonical: username.Canonical("frank")})
+		wantCode(t, err, errorz.CodeConflict)
+	})
+
+	t.Run("delete", func(t *testing.T) {
+		ctx, storages := open(t)
+		user := mustCreateUser(t, ctx, storages, newUser("heidi"))
+
+		if err := storages.Users.Delete(ctx, user.ID); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		_, err := storages.Users.GetByID(ctx, user.ID)
+		wantError(t, err, errorz.UserNotFound)
+		if err = storages.Users.Delete(ctx, user.ID); err != nil {
+			t.Errorf("Delete of a missing user: %v", err)
+		}
+	})
+
+	t.Run("get all pages in creation order", func(t *testing.T) {
+		ctx, storages := open(t)
+		// Databases shared with other data may already have users
+		before, err := storages.Users.GetAll(ctx, -1, 0)
+		if err != nil {
+			t.Fatalf("GetAll: %v", err)
+		}
+
+		created := map[string]bool{}
+		for _, name := range []string{"ivan", "judy", "mallory"} {
+			created[mustCreateUser(t, ctx, storages, newUser(name)).ID] = true
+		}
+
+		first, err := storages.Users.GetAll(ctx, 2, len(before))
+		if err != nil {
+			t.Fatalf("GetAll: %v", err)
+		}
+		second, err := storages.Users.GetAll(ctx, 2, len(before)+2)
+		if err != nil {
+			t.Fatalf("GetAll: %v", err)
+		}
+		if len(first) != 2 || len(second) != 1 {
+			t.Fatalf("pages of %d and %d users, want 2 and 1", len(first), len(second))
+		}
+		for _, user := range append(first, second...) {
+			if !created[user.ID] {
+				t.Errorf("unexpected user %s on the pages", user.ID)
+			}
+			delete(created, user.ID)
+		}
+	})
+}
+
+func runTokens(t *testing.T, open Open) {
+	t.Run("create and get", func(t *testing.T) {
+		ctx, storages := open(t)
+		user := mustCreateUser(t, ctx, storages, newUser("oscar"))
+		token := mustCreateToken(t, ctx, storages, user.ID, "refresh", time.Hour)
+
+		if _, err := uuid.Parse(token.ID); err != nil {
+			t.Errorf("id %q is not a uuid: %v", token.ID, err)
+		}
+
+		byToken, err := storages.Tokens.GetByToken(ctx, token.Token, "refresh")
+		if err != nil || byToken.ID != token.ID || byToken.UserID != user.ID {
+			t.Errorf("GetByToken = %v, %v, want token %s", byToken, err, token.ID)
+		}
+		byUser, err := storages.Tokens.GetByUserID(ctx, user.ID, "refresh")
+		if err != nil || byUser.ID != token.ID {
+			t.Errorf("GetByUserID = %v, %v, want token %s", byUser, err, token.ID)
+		}
+	})
+
+	t.Run("expired or other type is not found", func(t *testing.T) {
+		ctx, storages := open(t)
+		user := mustCreateUser(t, ctx, storages, newUser("peggy"))
+		expired := mustCreateToken(t, ctx, storages, user.ID, "refresh", -time.Minute)
+		valid := mustCreateToken(t, ctx, storages, user.ID, "access", time.Hour)
+
+		_, err := storages.Tokens.GetByToken(ctx, expired.Token, "refresh")
+		wantError(t, err, errorz.TokenNotFound)
+		_, err = storages.Tokens.GetByUserID(ctx, user.ID, "refresh")
+		wantError(t, err, errorz.TokenNotFound)
+		_, err = storages.Tokens.GetByToken(ctx, valid.Token, "refresh")
+		wantError(t, err, errorz.TokenNotFound)
+	})
+
+	t.Run("delete by type", func(t *testing.T) {
+		ctx, storages := open(t)
+		user := mustCreateUser(t, ctx, storages, newUser("rupert"))
+		refresh := mustCreateToken(t, ctx, storages, user.ID, "refresh", time.Hour)
+		access := mustCreateToken(t, ctx, storages, user.ID, "access", time.Hour)
+
+		if err := storages.Tokens.Delete(ctx, user.ID, "refresh"); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		_, err := storages.Tokens.GetByToken(ctx, refresh.Token, "refresh")
+		wantError(t, err, errorz.TokenNotFound)
+		if _, err = storages.Tokens.GetByToken(ctx, access.Token, "access"); err != nil {
+			t.Errorf("token of another type was deleted: %v", err)
+		}
+	})
+
+	t.Run("delete all of a user", func(t *testing.T) {
+		ctx, storages := open(t)
+		user := mustCreateUser(t, ctx, storages, newUser("sybil"))
+		other := mustCreateUser(t, ctx, storages, newUser("trent"))
+		mustCreateToken(t, ctx, storages, user.ID, "refresh", time.Hour)
+		mustCreateToken(t, ctx, storages, user.ID, "access", time.Hour)
+		otherToken := mustCreateToken(t, ctx, storages, other.ID, "refresh", time.Hour)
+
+		if err := storages.Tokens.DeleteAll(ctx, user.ID); err != nil {
+			t.Fatalf("DeleteAll: %v", err)
+		}
+		for _, tokenType := range []string{"refresh", "access"} {
+			_, err := storages.Tokens.GetByUserID(ctx, user.ID, tokenType)
+			wantError(t, err, errorz.TokenNotFound)
+		}
+		if _, err := storages.Tokens.GetByToken(ctx, otherToken.Token, "refresh"); err != nil {
+			t.Errorf("token of another user was deleted: %v", err)
+		}
+	})
+}
+
+func runOutbox(t *testing.T, open Open) {
+	t.Run("claim due emails once", func(t *testing.T) {
+		ctx, storages := open(t)
+		due := mustCreateOutboxEmail(t, ctx, storages, "due@example.com", time.Now().Add(-time.Minute))
+		mustCreateOutboxEmail(t, ctx, storages, "later@example.com", time.Now().Add(time.Hour))
+
+		if _, err := uuid.Parse(due.ID); err != nil {
+			t.Errorf("id %q is not a uuid: %v", due.ID, err)
+		}
+
+		claimed, err := storages.Outbox.Claim(ctx, 10, time.Minute)
+		if err != nil {
+			t.Fatalf("Claim: %v", err)
+		}
+		if len(claimed) != 1 || claimed[0].ID != due.ID || claimed[0].Email != due.Email {
+			t.Fatalf("Claim = %+v, want only %s", claimed, due.ID)
+		}
+		if !claimed[0].NextAttemptAt.After(time.Now()) {
+			t.Errorf("next_attempt_at = %v, want the end of the lease", claimed[0].NextAttemptAt)
+		}
+
+		claimed, err = storages.Outbox.Claim(ctx, 10, time.Minute)
+		if err != nil || len(claimed) != 0 {
+			t.Errorf("Claim of a leased email = %+v, %v, want none", claimed, err)
+		}
+	})
+
+	t.Run("claim respects the limit", func(t *testing.T) {
+		ctx, storages := open(t)
+		first := mustCreateOutboxEmail(t, ctx, storages, "first@example.com", time.Now().Add(-2*time.Minute))
+		mustCreateOutboxEmail(t, ctx, storages, "second@example.com", time.Now().Add(-time.Minute))
+
+		claimed, err := storages.Outbox.Claim(ctx, 1, time.Minute)
+		if err != nil || len(claimed) != 1 || claimed[0].ID != first.ID {
+			t.Errorf("Claim = %+v, %v, want the earliest due email %s", claimed, err, first.ID)
+		}
+	})
+
+	t.Run("sent and failed emails are not claimed", func(t *testing.T) {
+		ctx, storages := open(t)
+		sent := mustCreateOutboxEmail(t, ctx, storages, "sent@example.com", time.Now().Add(-time.Minute))
+		failed := mustCreateOutboxEmail(t, ctx, storages, "failed@example.com", time.Now().Add(-time.Minute))
+		retried := mustCreateOutboxEmail(t, ctx, storages, "retried@example.com", time.Now().Add(-time.Minute))
+
+		if err := storages.Outbox.MarkSent(ctx, sent.ID); err != nil {
+			t.Fatalf("MarkSent: %v", err)
+		}
+		if err := storages.Outbox.MarkFailed(ctx, failed.ID, "rejected", nil); err != nil {
+			t.Fatalf("MarkFailed: %v", err)
+		}
+		retryAt := time.Now().Add(-time.Second)
+		if err := storages.Outbox.MarkFailed(ctx, retried.ID, "timeout", &retryAt); err != nil {
+			t.Fatalf("MarkFailed with retry: %v", err)
+		}
+
+		claimed, err := storages.Outbox.Claim(ctx, 10, time.Minute)
+		if err != nil {
+			t.Fatalf("Claim: %v", err)
+		}
+		if len(claimed) != 1 || claimed[0].ID != retried.ID {
+			t.Fatalf("Claim = %+v, want only %s", claimed, retried.ID)
+		}
+		if claimed[0].Attempts != 1 || claimed[0].LastError != "timeout" {
+			t.Errorf("attempts, last_error = %d, %q, want 1, %q", claimed[0].Attempts, claimed[0].LastError, "timeout")
+		}
+	})
+}
+
+func runEmailEvents(t *testing.T, open Open) {
+	t.Run("get by user newest first", func(t *testing.T) {
+		ctx, storages := open(t)
+		user := mustCreateUser(t, ctx, storages, newUser("victor"))
+		now := time.Now()
+		older := mustCreateEmailEvent(t, ctx, storages, &user.ID, user.Email, now.Add(-time.Hour))
+		newer := mustCreateEmailEvent(t, ctx, storages, &user.ID, user.Email, now.Add(-time.Minute))
+		mustCreateEmailEvent(t, ctx, storages, nil, "unknown@example.com", now)
+
+		if _, err := uuid.Parse(older.ID); err != nil {
+			t.Errorf("id %q is not a uuid: %v", older.ID, err)
+		}
+
+		events, err := storages.EmailEvents.GetByUserID(ctx, user.ID, 10)
+		if err != nil {
+			t.Fatalf("GetByUserID: %v", err)
+		}
+		if len(events) != 2 || events[0].ID != newer.ID || events[1].ID != older.ID {
+			t.Errorf("GetByUserID = %+v, want %s, %s", events, newer.ID, older.ID)
+		}
+
+		events, err = storages.EmailEvents.GetByUserID(ctx, user.ID, 1)
+		if err != nil || len(events) != 1 || events[0].ID != newer.ID {
+			t.Errorf("GetByUserID with limit 1 = %+v, %v, want %s", events, err, newer.ID)
+		}
+	})
+}
+
+func runTransactions(t *testing.T, open Open) {
+	errRollback := errors.New("rollback")
+
+	t.Run("commit keeps changes", func(t *testing.T) {
+		ctx, storages := open(t)
+		var user *entity.User
+		err := storages.Transactor.Do(ctx, func(ctx context.Context) error {
+			user = mustCreateUser(t, ctx, storages, newUser("walter"))
+			mustCreateToken(t, ctx, storages, user.ID, "refresh", time.Hour)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Do: %v", err)
+		}
+
+		if _, err = storages.Users.GetByID(ctx, user.ID); err != nil {
+			t.Errorf("committed user: %v", err)
+		}
+		if _, err = storages.Tokens.GetByUserID(ctx, user.ID, "refresh"); err != nil {
+			t.Errorf("committed token: %v", err)
+		}
+	})
+
+	t.Run("error rolls back every storage", func(t *testing.T) {
+		ctx, storages := open(t)
+		var user *entity.User
+		err := storages.Transactor.Do(ctx, func(ctx context.Context) error {
+			user = mustCreateUser(t, ctx, storages, newUser("xavier"))
+			mustCreateToken(t, ctx, storages, user.ID, "refresh", time.Hour)
+			mustCreateOutboxEmail(t, ctx, storages, user.Email, time.Now().Add(-time.Minute))
+			mustCreateEmailEvent(t, ctx, storages, &user.ID, user.Email, time.Now())
+			return errRollback
+		})
+		if !errors.Is(err, errRollback) {
+			t.Fatalf("Do: error = %v, want %v", err, errRollback)
+		}
+
+		_, err = storages.Users.GetByID(ctx, user.ID)
+		wantError(t, err, errorz.UserNotFound)
+		_, err = storages.Tokens.GetByUserID(ctx, user.ID, "refresh")
+		wantError(t, err, errorz.TokenNotFound)
+		if claimed, err := storages.Outbox.Claim(ctx, 10, time.Minute); err != nil || len(claimed) != 0 {
+			t.Errorf("Claim after rollback = %+v, %v, want none", claimed, err)
+		}
+		if events, err := storages.EmailEvents.GetByUserID(ctx, user.ID, 10); err != nil || len(events) != 0 {
+			t.Errorf("GetByUserID after rollback = %+v, %v, want none", events, err)
+		}
+	})
+
+	t.Run("nested rollback keeps outer changes", func(t *testing.T) {
+		ctx, storages := open(t)
+		var outer, inner *entity.User
+		err := storages.Transactor.Do(ctx, func(ctx context.Context) error {
+			outer = mustCreateUser(t, ctx, storages, newUser("yvonne"))
+			err := storages.Transactor.Do(ctx, func(ctx context.Context) error {
+				inner = mustCreateUser(t, ctx, storages, newUser("zoe"))
+				return errRollback
+			})
+			if !errors.Is(err, errRollback) {
+				t.Errorf("nested Do: error = %v, want %v", err, errRollback)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Do: %v", err)
+		}
+
+		if _, err = storages.Users.GetByID(ctx, outer.ID); err != nil {
+			t.Errorf("outer user: %v", err)
+		}
+		_, err = storages.Users.GetByID(ctx, inner.ID)
+		wantError(t, err, errorz.UserNotFound)
+	})
+}
+
+// newUser is a function that returns a user with the canonical fields set, as the user service does.
+func newUser(name string) entity.User {
+	email := name + "@example.com"
+	return entity.User{
+		Email:             email,
+		Username:          name,
+		EmailCanonical:    username.CanonicalEmail(email),
+		UsernameCanonical: username.Canonical(name),
+		Password:          []byte("hash of " + name),
+	}
+}
+
+func mustCreateUser(t *testing.T, ctx context.Context, storages Storages, user entity.User) *entity.User {
+	t.Helper()
+	created, err := storages.Users.Create(ctx, user)
+	if err != nil {
+		t.Fatalf("Create user %s: %v", user.Username, err)
+	}
+	return created
+}
+
+func mustCreateToken(t *testing.T, ctx context.Context, storages Storages, userID, tokenType string, expiresIn time.Duration) *entity.Token {
+	t.Helper()
+	created, err := storages.Tokens.Create(ctx, entity.Token{
+		Token:   uuid.NewString(),
+		UserID:  userID,
+		Type:    tokenType,
+		Expires: time.Now().Add(expiresIn),
+	})
+	if err != nil {
+		t.Fatalf("Create token: %v", err)
+	}
+	return created
+}
+
+func mustCreateOutboxEmail(t *testing.T, ctx context.Context, storages Storages, email string, nextAttemptAt time.Time) *entity.OutboxEmail {
+	t.Helper()
+	created, err := storages.Outbox.Create(ctx, entity.OutboxEmail{
+		Email:         email,
+		Subject:       "Subject",
+		Body:          "Body",
+		NextAttemptAt: nextAttemptAt,
+	})
+	if err != nil {
+		t.Fatalf("Create outbox email: %v", err)
+	}
+	return created
+}
+
+func mustCreateEmailEvent(t *testing.T, ctx context.Context, storages Storages, userID *string, email string, occurredAt time.Time) *entity.EmailEvent {
+	t.Helper()
+	created, err := storages.EmailEvents.Create(ctx, entity.EmailEvent{
+		UserID:     userID,
+		Email:      email,
+		Type:       entity.EmailEventBounce,
+		OccurredAt: occurredAt,
+	})
+	if err != nil {
+		t.Fatalf("Create email event: %v", err)
+	}
+	return created
+}
+
+func wantError(t *testing.T, err error, want *errorz.Error) {
+	t.Helper()
+	if !errors.Is(err, want) {
+		t.Errorf("error = %v, want %v", err, want)
+	}
+}
+
+func wantCode(t *testing.T, err error, want errorz.Code) {
+	t.Helper()
+	var domainErr *errorz.Error
+	if !errors.As(err, &domainErr) || domainErr.Code != want {
+		t.Errorf("error = %v, want code %s", err, want)
+	}
+}
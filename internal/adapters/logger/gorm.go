@@ -0,0 +1,81 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"go.uber.org/zap/zapcore"
+	"gorm.io/gorm"
+	gormLogger "gorm.io/gorm/logger"
+	"time"
+	"webTemplate/internal/domain/common/secret"
+)
+
+// gormAdapter is a struct that writes gorm logs to the ComponentDB logger, so their level can be changed at runtime.
+type gormAdapter struct {
+	slowThreshold time.Duration
+	logParams     bool
+}
+
+// Gorm is a function that returns a gorm logger writing to the ComponentDB logger of the request in the context.
+// Queries are logged at debug level, queries slower than slowThreshold at warn level and failed queries at error level.
+/*
+ * slowThreshold time.Duration - queries running longer are logged at warn level, 0 disables it
+ * logParams bool - if false, values bound to queries like emails and password hashes are masked
+ */
+func Gorm(slowThreshold time.Duration, logParams bool) gormLogger.Interface {
+	return &gormAdapter{slowThreshold: slowThreshold, logParams: logParams}
+}
+
+// LogMode is a method required by gorm, levels are controlled by the ComponentDB level instead.
+func (a *gormAdapter) LogMode(gormLogger.LogLevel) gormLogger.Interface {
+	return a
+}
+
+func (a *gormAdapter) Info(ctx context.Context, msg string, args ...interface{}) {
+	dbLogger(ctx).Infof(msg, args...)
+}
+
+func (a *gormAdapter) Warn(ctx context.Context, msg string, args ...interface{}) {
+	dbLogger(ctx).Warnf(msg, args...)
+}
+
+func (a *gormAdapter) Error(ctx context.Context, msg string, args ...interface{}) {
+	dbLogger(ctx).Errorf(msg, args...)
+}
+
+// ParamsFilter is a method called by gorm before logging a query, it masks the bound values unless logParams is set.
+func (a *gormAdapter) ParamsFilter(_ context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	if a.logParams {
+		return sql, params
+	}
+
+	masked := make([]interface{}, len(params))
+	for i := range params {
+		masked[i] = secret.Mask
+	}
+	return sql, masked
+}
+
+// Trace is a method that logs an executed query with its duration and affected rows.
+// Not found errors are expected by storages and are not logged as errors.
+func (a *gormAdapter) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	elapsed := time.Since(begin)
+	log := dbLogger(ctx)
+
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound):
+		sql, rows := fc()
+		log.Errorw("query failed", "sql", sql, "rows", rows, "elapsed", elapsed, "error", err)
+	case a.slowThreshold > 0 && elapsed > a.slowThreshold:
+		sql, rows := fc()
+		log.Warnw("slow query", "sql", sql, "rows", rows, "elapsed", elapsed, "threshold", a.slowThreshold)
+	case enabled(ComponentDB, zapcore.DebugLevel):
+		sql, rows := fc()
+		log.Debugw("query", "sql", sql, "rows", rows, "elapsed", elapsed)
+	}
+}
+
+// dbLogger is a function that returns the ComponentDB logger of the request in the context.
+func dbLogger(ctx context.Context) *logger {
+	return FromContext(ctx).Named(ComponentDB)
+}
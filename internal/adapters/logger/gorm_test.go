@@ -0,0 +1,83 @@
+package logger
+
+import (
+	"context"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"strings"
+	"testing"
+	"time"
+	"webTemplate/internal/domain/common/secret"
+)
+
+func TestGormParamsFilter(t *testing.T) {
+	sql := "SELECT * FROM users WHERE email = ? AND password = ?"
+	params := []interface{}{"user@example.com", []byte("$2a$12$hash")}
+
+	gotSQL, masked := Gorm(0, false).(*gormAdapter).ParamsFilter(context.Background(), sql, params...)
+	if gotSQL != sql || len(masked) != len(params) {
+		t.Fatalf("ParamsFilter() = %q, %v", gotSQL, masked)
+	}
+	for i, value := range masked {
+		if value != secret.Mask {
+			t.Errorf("param %d = %v, want it masked", i, value)
+		}
+	}
+
+	_, kept := Gorm(0, true).(*gormAdapter).ParamsFilter(context.Background(), sql, params...)
+	if kept[0] != params[0] {
+		t.Errorf("ParamsFilter() with logParams = %v, want the params", kept)
+	}
+}
+
+func TestGormLogsMaskedParams(t *testing.T) {
+	type account struct {
+		ID       int
+		Email    string
+		Password string
+	}
+
+	tests := []struct {
+		name      string
+		logParams bool
+		wantShown bool
+	}{
+		{"masked", false, false},
+		{"shown with sql-params", true, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logs := observe(t, nil)
+
+			db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: Gorm(time.Second, tt.logParams)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err = db.AutoMigrate(&account{}); err != nil {
+				t.Fatal(err)
+			}
+			if err = db.Create(&account{Email: "user@example.com", Password: "s3cr3t-hash"}).Error; err != nil {
+				t.Fatal(err)
+			}
+
+			var queries []string
+			for _, entry := range logs.All() {
+				if sql, ok := entry.ContextMap()["sql"].(string); ok && entry.LoggerName == ComponentDB && strings.HasPrefix(sql, "INSERT") {
+					queries = append(queries, sql)
+				}
+			}
+			if len(queries) != 1 {
+				t.Fatalf("got %d logged inserts, want 1", len(queries))
+			}
+
+			shown := strings.Contains(queries[0], "s3cr3t-hash") || strings.Contains(queries[0], "user@example.com")
+			if shown != tt.wantShown {
+				t.Errorf("logged query %q shows params = %t, want %t", queries[0], shown, tt.wantShown)
+			}
+			if !tt.wantShown && !strings.Contains(queries[0], secret.Mask) {
+				t.Errorf("logged query %q has no masked params", queries[0])
+			}
+		})
+	}
+}
@@ -0,0 +1,204 @@
+package logger
+
+import (
+	"fmt"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Components of the app with independent log levels, loggers get them with Named.
+// Entries of loggers without a component use the level of ComponentApp.
+const (
+	ComponentApp   = "app"
+	ComponentHTTP  = "http"
+	ComponentDB    = "db"
+	ComponentEmail = "email"
+	ComponentAuth  = "auth"
+)
+
+// Components is a list of all components with their own log level.
+var Components = []string{ComponentApp, ComponentHTTP, ComponentDB, ComponentEmail, ComponentAuth}
+
+// componentLevel is a struct that contains the current and the configured level of a component.
+type componentLevel struct {
+	current    zap.AtomicLevel
+	configured zapcore.Level
+	revert     *time.Timer // Pending revert of a temporary level
+	revertAt   time.Time
+}
+
+// LevelStatus is a struct that describes the log level of a component.
+type LevelStatus struct {
+	Component  string
+	Level      string     // Current level
+	Configured string     // Level from the config, restored when a temporary level expires
+	RevertAt   *time.Time // Expiration of the temporary level, nil if the configured level is used
+}
+
+var (
+	levelsMu sync.Mutex
+	levels   = newLevels()
+)
+
+func newLevels() map[string]*componentLevel {
+	componentLevels := make(map[string]*componentLevel, len(Components))
+	for _, component := range Components {
+		componentLevels[component] = &componentLevel{
+			current:    zap.NewAtomicLevelAt(zapcore.InfoLevel),
+			configured: zapcore.InfoLevel,
+		}
+	}
+	return componentLevels
+}
+
+// SetLevels is a function that applies configured levels: base for every component without its own level.
+// Temporary levels set with SetTemporaryLevel stay until they expire, then the new configured level is restored.
+/*
+ * base string - level name: "debug", "info", "warn" or "error"
+ * components map[string]string - levels of single components, e.g. {"db": "warn"}
+ */
+func SetLevels(base string, components map[string]string) error {
+	baseLevel, err := zapcore.ParseLevel(base)
+	if err != nil {
+		return err
+	}
+
+	configured := make(map[string]zapcore.Level, len(Components))
+	for _, component := range Components {
+		configured[component] = baseLevel
+	}
+	for component, name := range components {
+		if _, ok := levels[component]; !ok {
+			return fmt.Errorf("unknown log component %q, expected one of: %s", component, strings.Join(Components, ", "))
+		}
+		if name == "" {
+			continue
+		}
+		if configured[component], err = zapcore.ParseLevel(name); err != nil {
+			return err
+		}
+	}
+
+	levelsMu.Lock()
+	defer levelsMu.Unlock()
+
+	for component, level := range configured {
+		componentLevel := levels[component]
+		componentLevel.configured = level
+		if componentLevel.revert == nil {
+			componentLevel.current.SetLevel(level)
+		}
+	}
+	return nil
+}
+
+// SetTemporaryLevel is a function that changes the level of a component until the duration passes.
+// The configured level is restored afterwards, a new temporary level replaces the previous one.
+func SetTemporaryLevel(component, name string, duration time.Duration) error {
+	level, err := zapcore.ParseLevel(name)
+	if err != nil {
+		return err
+	}
+
+	levelsMu.Lock()
+	defer levelsMu.Unlock()
+
+	componentLevel, ok := levels[component]
+	if !ok {
+		return fmt.Errorf("unknown log component %q, expected one of: %s", component, strings.Join(Components, ", "))
+	}
+
+	if componentLevel.revert != nil {
+		componentLevel.revert.Stop()
+	}
+	componentLevel.current.SetLevel(level)
+	componentLevel.revertAt = time.Now().Add(duration)
+
+	var revert *time.Timer
+	revert = time.AfterFunc(duration, func() {
+		levelsMu.Lock()
+		defer levelsMu.Unlock()
+
+		// A newer temporary level has its own timer
+		if componentLevel.revert != revert {
+			return
+		}
+		componentLevel.current.SetLevel(componentLevel.configured)
+		componentLevel.revert = nil
+		Log.Infof("Log level of %s reverted to %s", component, componentLevel.configured)
+	})
+	componentLevel.revert = revert
+	return nil
+}
+
+// Levels is a function that returns the log levels of all components, sorted by component.
+func Levels() []LevelStatus {
+	levelsMu.Lock()
+	defer levelsMu.Unlock()
+
+	statuses := make([]LevelStatus, 0, len(levels))
+	for component, componentLevel := range levels {
+		status := LevelStatus{
+			Component:  component,
+			Level:      componentLevel.current.Level().String(),
+			Configured: componentLevel.configured.String(),
+		}
+		if componentLevel.revert != nil {
+			revertAt := componentLevel.revertAt
+			status.RevertAt = &revertAt
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Component < statuses[j].Component })
+	return statuses
+}
+
+// levelOf is a function that returns the level of the component a logger name belongs to.
+// The last known component of the dotted name wins, e.g. "http.auth" uses the level of "auth".
+func levelOf(loggerName string) zapcore.Level {
+	component := ComponentApp
+	for _, name := range strings.Split(loggerName, ".") {
+		if _, ok := levels[name]; ok {
+			component = name
+		}
+	}
+	return levels[component].current.Level()
+}
+
+// minLevel is a function that returns the lowest level enabled for any component.
+func minLevel() zapcore.Level {
+	lowest := zapcore.FatalLevel
+	for _, componentLevel := range levels {
+		lowest = min(lowest, componentLevel.current.Level())
+	}
+	return lowest
+}
+
+// enabled is a function that reports whether entries of the level are written for the logger name.
+func enabled(loggerName string, level zapcore.Level) bool {
+	return level >= levelOf(loggerName)
+}
+
+// levelCore is a zapcore.Core that filters entries by the level of their component.
+type levelCore struct {
+	zapcore.Core
+}
+
+func (c *levelCore) Enabled(level zapcore.Level) bool {
+	return level >= minLevel()
+}
+
+func (c *levelCore) With(fields []zapcore.Field) zapcore.Core {
+	return &levelCore{Core: c.Core.With(fields)}
+}
+
+func (c *levelCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if enabled(entry.LoggerName, entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
@@ -1,12 +1,17 @@
 package logger
 
 import (
+	"fmt"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
-	"os"
 	"time"
 )
 
+const (
+	FormatConsole = "console"
+	FormatJSON    = "json"
+)
+
 var (
 	Log *logger
 )
@@ -15,46 +20,124 @@ type logger struct {
 	*zap.SugaredLogger
 }
 
+// Options is a struct that contains the settings of the logger.
+type Options struct {
+	Debug    bool           // Enables the debug level
+	Location *time.Location // Time zone of timestamps, UTC if nil
+	Format   string         // FormatConsole (default) or FormatJSON
+	Outputs  []string       // "stdout", "stderr" or file paths, stdout if empty
+	// RedactKeys are masked field keys, DefaultRedactKeys if empty
+	RedactKeys []string
+}
+
 // New is a function to initialize logger
 /*
- * debug bool - is debug mode
- * timeZone string - logger time zone, by default "GMT"
+ * options Options - format, outputs, time zone and level of the logger
  */
-func New(debug bool, timeZone string) {
+func New(options Options) error {
+	if options.Location == nil {
+		options.Location = time.UTC
+	}
+	if len(options.Outputs) == 0 {
+		options.Outputs = []string{"stdout"}
+	}
+
 	encoderConfig := zapcore.EncoderConfig{
 		MessageKey:     "message",
 		LevelKey:       "level",
 		TimeKey:        "timestamp",
 		CallerKey:      "caller",
 		EncodeLevel:    zapcore.CapitalColorLevelEncoder, // Цветная подсветка уровней
-		EncodeTime:     customTimeEncoder,                // Кастомный формат времени
+		EncodeTime:     timeEncoder(options.Location),    // Кастомный формат времени
 		EncodeCaller:   zapcore.ShortCallerEncoder,       // Краткий формат caller
 		EncodeDuration: zapcore.StringDurationEncoder,
 	}
 
-	if timeZone != "" {
-		encoderConfig.EncodeTime = func(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
-			enc.AppendString(t.In(time.FixedZone(timeZone, 3*60*60)).Format("2006-01-02 15:04:05"))
+	var encoder zapcore.Encoder
+	switch options.Format {
+	case FormatJSON:
+		encoderConfig.EncodeLevel = zapcore.LowercaseLevelEncoder
+		encoderConfig.EncodeTime = rfc3339TimeEncoder(options.Location)
+		encoderConfig.EncodeDuration = millisDurationEncoder
+		encoder = zapcore.NewJSONEncoder(encoderConfig)
+	case FormatConsole, "":
+		if !onlyTerminals(options.Outputs) {
+			encoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
 		}
+		encoder = zapcore.NewConsoleEncoder(encoderConfig)
+	default:
+		return fmt.Errorf("unknown log format %q", options.Format)
 	}
 
-	var level zapcore.Level
-	if debug {
-		level = zapcore.DebugLevel
-	} else {
-		level = zapcore.InfoLevel
+	output, _, err := zap.Open(options.Outputs...)
+	if err != nil {
+		return fmt.Errorf("open log outputs: %w", err)
 	}
 
-	consoleEncoder := zapcore.NewConsoleEncoder(encoderConfig)
-	core := zapcore.NewCore(consoleEncoder, zapcore.Lock(os.Stdout), level)
+	baseLevel := "info"
+	if options.Debug {
+		baseLevel = "debug"
+	}
+	if err = SetLevels(baseLevel, nil); err != nil {
+		return err
+	}
+
+	// Levels are checked per component by levelCore, sensitive fields are masked by redactCore
+	core := &levelCore{Core: newRedactCore(zapcore.NewCore(encoder, output, zapcore.DebugLevel), options.RedactKeys)}
 	log := zap.New(core, zap.AddCaller())
 
 	Log = &logger{
 		SugaredLogger: log.Sugar(),
 	}
+	return nil
+}
+
+// With is a method that returns a child logger adding the key-value pairs to every entry.
+func (l *logger) With(args ...interface{}) *logger {
+	return &logger{SugaredLogger: l.SugaredLogger.With(args...)}
 }
 
-// customTimeEncoder форматирует время в GMT+0
-func customTimeEncoder(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
-	enc.AppendString(t.In(time.FixedZone("GMT+0", 3*60*60)).Format("2006-01-02 15:04:05"))
+// Named is a method that returns a child logger of a component, its entries use the level of the component.
+/*
+ * component string - one of Components, e.g. ComponentAuth
+ */
+func (l *logger) Named(component string) *logger {
+	return &logger{SugaredLogger: l.SugaredLogger.Named(component)}
+}
+
+// Sync is a function that flushes buffered log entries, it is called before the app exits.
+func Sync() error {
+	if Log == nil {
+		return nil
+	}
+	return Log.Sync()
+}
+
+// onlyTerminals is a function that reports whether all outputs are standard streams, where colors can be used.
+func onlyTerminals(outputs []string) bool {
+	for _, output := range outputs {
+		if output != "stdout" && output != "stderr" {
+			return false
+		}
+	}
+	return true
+}
+
+// timeEncoder форматирует время в заданном часовом поясе
+func timeEncoder(location *time.Location) zapcore.TimeEncoder {
+	return func(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
+		enc.AppendString(t.In(location).Format("2006-01-02 15:04:05"))
+	}
+}
+
+// millisDurationEncoder форматирует длительность для JSON в миллисекундах с дробной частью
+func millisDurationEncoder(d time.Duration, enc zapcore.PrimitiveArrayEncoder) {
+	enc.AppendFloat64(float64(d) / float64(time.Millisecond))
+}
+
+// rfc3339TimeEncoder форматирует время для JSON в RFC 3339 с долями секунды и смещением
+func rfc3339TimeEncoder(location *time.Location) zapcore.TimeEncoder {
+	return func(t time.Time, enc zapcore.PrimitiveArrayEncoder) {
+		enc.AppendString(t.In(location).Format(time.RFC3339Nano))
+	}
 }
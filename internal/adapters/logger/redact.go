@@ -0,0 +1,83 @@
+package logger
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"strings"
+	"webTemplate/internal/domain/common/secret"
+)
+
+// DefaultRedactKeys are the field keys masked when Options.RedactKeys is empty.
+var DefaultRedactKeys = []string{"password", "token", "authorization", "api_key"}
+
+// redactCore is a zapcore.Core that masks the values of fields with sensitive keys.
+// A key is sensitive if it contains one of the keys, ignoring case, "-" and "_": "api_key" masks "X-API-Key" and "apiKey".
+type redactCore struct {
+	zapcore.Core
+	keys []string
+}
+
+// newRedactCore is a function that wraps a core to mask fields with the given keys.
+func newRedactCore(core zapcore.Core, keys []string) zapcore.Core {
+	if len(keys) == 0 {
+		keys = DefaultRedactKeys
+	}
+
+	normalized := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if key = normalizeKey(key); key != "" {
+			normalized = append(normalized, key)
+		}
+	}
+	return &redactCore{Core: core, keys: normalized}
+}
+
+func (c *redactCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactCore{Core: c.Core.With(c.redact(fields)), keys: c.keys}
+}
+
+func (c *redactCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *redactCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	return c.Core.Write(entry, c.redact(fields))
+}
+
+// redact is a method that returns the fields with sensitive values replaced by secret.Mask.
+// The slice is copied only if a field is masked.
+func (c *redactCore) redact(fields []zapcore.Field) []zapcore.Field {
+	var redacted []zapcore.Field
+	for i, field := range fields {
+		if !c.sensitive(field.Key) {
+			continue
+		}
+		if redacted == nil {
+			redacted = append([]zapcore.Field(nil), fields...)
+		}
+		redacted[i] = zap.String(field.Key, secret.Mask)
+	}
+	if redacted == nil {
+		return fields
+	}
+	return redacted
+}
+
+// sensitive is a method that reports whether a field key contains one of the redacted keys.
+func (c *redactCore) sensitive(key string) bool {
+	key = normalizeKey(key)
+	for _, redactKey := range c.keys {
+		if strings.Contains(key, redactKey) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeKey is a function that lower-cases a key and removes "-" and "_".
+func normalizeKey(key string) string {
+	return strings.NewReplacer("-", "", "_", "").Replace(strings.ToLower(key))
+}
@@ -0,0 +1,108 @@
+package logger
+
+import (
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"testing"
+	"webTemplate/internal/domain/common/secret"
+)
+
+// observe is a function that replaces Log with a logger writing through the level and redact cores to an observer.
+// Log and the levels are restored when the test completes.
+func observe(t *testing.T, redactKeys []string) *observer.ObservedLogs {
+	t.Helper()
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	previous := Log
+	Log = &logger{SugaredLogger: zap.New(&levelCore{Core: newRedactCore(core, redactKeys)}).Sugar()}
+	if err := SetLevels("debug", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		Log = previous
+		_ = SetLevels("info", nil)
+	})
+	return logs
+}
+
+func TestRedactDefaultKeys(t *testing.T) {
+	logs := observe(t, nil)
+
+	Log.Infow("login",
+		"password", "hunter2",
+		"refresh_token", "eyJhbGciOi.refresh",
+		"accessToken", "eyJhbGciOi.access",
+		"Authorization", "Bearer eyJhbGciOi",
+		"X-API-Key", "mk_live_123",
+		"apiKey", "mk_live_456",
+		"new-password", "hunter3",
+		"email", "user@example.com",
+		"user_id", "42",
+	)
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want 1", len(entries))
+	}
+	fields := entries[0].ContextMap()
+
+	for _, key := range []string{"password", "refresh_token", "accessToken", "Authorization", "X-API-Key", "apiKey", "new-password"} {
+		if fields[key] != secret.Mask {
+			t.Errorf("field %q = %v, want it masked", key, fields[key])
+		}
+	}
+	for key, want := range map[string]string{"email": "user@example.com", "user_id": "42"} {
+		if fields[key] != want {
+			t.Errorf("field %q = %v, want %q", key, fields[key], want)
+		}
+	}
+}
+
+func TestRedactWith(t *testing.T) {
+	logs := observe(t, nil)
+
+	Log.With("token", "eyJhbGciOi.child", "request_id", "req-1").Named(ComponentAuth).Warnw("refresh failed", "password", "hunter2")
+
+	entries := logs.All()
+	if len(entries) != 1 {
+		t.Fatalf("got %d entries, want 1", len(entries))
+	}
+	fields := entries[0].ContextMap()
+	if fields["token"] != secret.Mask || fields["password"] != secret.Mask {
+		t.Errorf("fields = %v, want token and password masked", fields)
+	}
+	if fields["request_id"] != "req-1" {
+		t.Errorf("field request_id = %v, want \"req-1\"", fields["request_id"])
+	}
+}
+
+func TestRedactCustomKeys(t *testing.T) {
+	logs := observe(t, []string{"SSN", "card-number", ""})
+
+	Log.Infow("payment", "ssn", "123-45-6789", "cardNumber", "4111111111111111", "password", "kept", "amount", 10)
+
+	fields := logs.All()[0].ContextMap()
+	if fields["ssn"] != secret.Mask || fields["cardNumber"] != secret.Mask {
+		t.Errorf("fields = %v, want ssn and cardNumber masked", fields)
+	}
+	// Custom keys replace the defaults, an empty key doesn't mask everything
+	if fields["password"] != "kept" || fields["amount"] != int64(10) {
+		t.Errorf("fields = %v, want password and amount kept", fields)
+	}
+}
+
+func TestRedactKeepsFields(t *testing.T) {
+	core := newRedactCore(zapcore.NewNopCore(), nil).(*redactCore)
+
+	fields := []zapcore.Field{zap.String("password", "hunter2"), zap.String("email", "user@example.com")}
+	redacted := core.redact(fields)
+	if redacted[0].String != secret.Mask || redacted[1].String != "user@example.com" {
+		t.Errorf("redact() = %v", redacted)
+	}
+	// The caller's fields must stay untouched, they may be reused by other cores
+	if fields[0].String != "hunter2" {
+		t.Errorf("redact() changed the original field to %q", fields[0].String)
+	}
+}
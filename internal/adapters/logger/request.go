@@ -0,0 +1,69 @@
+package logger
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"time"
+)
+
+type contextKey struct{}
+
+// ContextKey is the key of the request-scoped logger in fiber locals and contexts.
+var ContextKey = contextKey{}
+
+// FromContext is a function that returns the request-scoped logger stored in the context, Log if there is none.
+// Fiber contexts returned by c.Context() expose fiber locals, so use cases can log with request fields.
+func FromContext(ctx context.Context) *logger {
+	if ctx != nil {
+		if requestLogger, ok := ctx.Value(ContextKey).(*logger); ok {
+			return requestLogger
+		}
+	}
+	return Log
+}
+
+// WithContext is a function that returns a copy of the context carrying the logger.
+func WithContext(ctx context.Context, l *logger) context.Context {
+	return context.WithValue(ctx, ContextKey, l)
+}
+
+// ForRequest is a function that returns a child of Log adding the request fields and,
+// when each entry is written, the request latency and route.
+/*
+ * start time.Time - the time the request was received
+ * route func() string - returns the route template, e.g. "/api/v1/admin/users/:id", evaluated on every entry
+ * fields ...interface{} - key-value pairs like "request_id", id
+ */
+func ForRequest(start time.Time, route func() string, fields ...interface{}) *logger {
+	requestLogger := Log.Desugar().WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return &requestCore{Core: core, start: start, route: route}
+	}))
+	return &logger{SugaredLogger: requestLogger.Sugar().With(fields...)}
+}
+
+// requestCore is a zapcore.Core that adds the fields known only when an entry is written.
+type requestCore struct {
+	zapcore.Core
+	start time.Time
+	route func() string
+}
+
+func (c *requestCore) With(fields []zapcore.Field) zapcore.Core {
+	return &requestCore{Core: c.Core.With(fields), start: c.start, route: c.route}
+}
+
+func (c *requestCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if enabled(entry.LoggerName, entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *requestCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	fields = append(fields[:len(fields):len(fields)],
+		zap.String("route", c.route()),
+		zap.Duration("latency", time.Since(c.start)),
+	)
+	return c.Core.Write(entry, fields)
+}
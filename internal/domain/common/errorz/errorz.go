@@ -2,8 +2,109 @@ package errorz
 
 import "errors"
 
+// Code is a machine-readable category of a domain error, transport adapters map it to their own status codes.
+type Code string
+
+const (
+	CodeNotFound        Code = "not_found"
+	CodeConflict        Code = "conflict"
+	CodeUnauthorized    Code = "unauthorized"
+	CodeForbidden       Code = "forbidden"
+	CodeValidation      Code = "validation"
+	CodeTooManyRequests Code = "too_many_requests"
+	CodeUnavailable     Code = "unavailable"
+	CodeInternal        Code = "internal"
+)
+
+// Error is a domain error with a code, a message safe to show to clients and an optional wrapped cause.
+type Error struct {
+	Code    Code
+	Message string
+	Fields  []FieldError
+	Err     error
+}
+
+// FieldError is a problem of a single input field, it is reported together with CodeValidation errors.
+type FieldError struct {
+	Field   string            // Name of the field as seen by clients
+	Rule    string            // Name of the failed rule
+	Message string            // Human-readable description of the problem
+	Params  map[string]string // Params of the rule, used to render messages
+}
+
+// New is a function that creates a new domain error.
+func New(code Code, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+// Wrap is a function that creates a new domain error caused by err.
+func Wrap(err error, code Code, message string) *Error {
+	return &Error{Code: code, Message: message, Err: err}
+}
+
+// Validation is a function that creates a new validation error with field-level problems.
+func Validation(message string, fields []FieldError) *Error {
+	return &Error{Code: CodeValidation, Message: message, Fields: fields}
+}
+
+// Internal is a function that wraps an unexpected error, its details must not reach clients.
+func Internal(err error) *Error {
+	return Wrap(err, CodeInternal, "internal server error")
+}
+
+// Error is a method that returns the message together with the cause, it is meant for logs, not for clients.
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+// Unwrap is a method that returns the cause of the error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is is a method that reports whether target is the same domain error, ignoring the wrapped cause.
+func (e *Error) Is(target error) bool {
+	var t *Error
+	if !errors.As(target, &t) {
+		return false
+	}
+	return e.Code == t.Code && e.Message == t.Message
+}
+
+// Wrap is a method that returns a copy of the error caused by err, the copy still matches the original with errors.Is.
+func (e *Error) Wrap(err error) *Error {
+	return &Error{Code: e.Code, Message: e.Message, Fields: e.Fields, Err: err}
+}
+
+// CodeOf is a function that returns the code of the first domain error in the chain, CodeInternal if there is none.
+func CodeOf(err error) Code {
+	var domainErr *Error
+	if errors.As(err, &domainErr) {
+		return domainErr.Code
+	}
+	return CodeInternal
+}
+
 var (
-	EmailAlreadyTaken = errors.New("email already taken")
-	AuthHeaderIsEmpty = errors.New("auth header is empty")
-	Forbidden         = errors.New("forbidden")
+	NotFound             = New(CodeNotFound, "not found")
+	UserNotFound         = New(CodeNotFound, "user not found")
+	TokenNotFound        = New(CodeNotFound, "token not found")
+	LogComponentNotFound = New(CodeNotFound, "log component not found")
+	EmailAlreadyTaken    = New(CodeConflict, "email already taken")
+	UsernameAlreadyTaken = New(CodeConflict, "username already taken")
+	AlreadyVerified      = New(CodeConflict, "already verified")
+	AuthHeaderIsEmpty    = New(CodeUnauthorized, "auth header is empty")
+	InvalidCredentials   = New(CodeUnauthorized, "invalid email or password")
+	InvalidToken         = New(CodeUnauthorized, "invalid token")
+	InvalidSignature     = New(CodeUnauthorized, "invalid webhook signature")
+	Forbidden            = New(CodeForbidden, "forbidden")
+	InvalidCode          = New(CodeForbidden, "invalid code")
+	InvalidEmail         = New(CodeValidation, "invalid email")
+	InvalidBody          = New(CodeValidation, "invalid request body")
+	EmailUndeliverable   = New(CodeValidation, "email address is undeliverable")
+	TooManyRequests      = New(CodeTooManyRequests, "too many requests")
+	WebhookNotConfigured = New(CodeUnavailable, "webhook secret is not configured")
 )
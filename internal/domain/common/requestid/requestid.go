@@ -0,0 +1,42 @@
+package requestid
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"regexp"
+)
+
+// Header is the HTTP header carrying the request id, both in API requests and in calls to other services.
+const Header = "X-Request-ID"
+
+// validPattern matches ids accepted from clients: short and safe to log and forward.
+var validPattern = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+type contextKey struct{}
+
+// ContextKey is the key of the request id in fiber locals and contexts.
+var ContextKey = contextKey{}
+
+// New is a function that generates a new request id.
+func New() string {
+	return uuid.NewString()
+}
+
+// Valid is a function that reports whether an id received from a client can be propagated as is.
+func Valid(id string) bool {
+	return validPattern.MatchString(id)
+}
+
+// FromContext is a function that returns the request id stored in the context, "" if there is none.
+func FromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(ContextKey).(string)
+	return id
+}
+
+// WithContext is a function that returns a copy of the context carrying the request id.
+func WithContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ContextKey, id)
+}
@@ -0,0 +1,36 @@
+package secret
+
+// Mask is the text printed instead of a secret value.
+const Mask = "***"
+
+// Secret is a string that is masked when it is printed, logged or marshaled, e.g. a password or an api key.
+// Use Reveal where the real value is needed.
+type Secret string
+
+// Reveal is a method that returns the real value of the secret.
+func (s Secret) Reveal() string {
+	return string(s)
+}
+
+// String is a method that returns Mask, or an empty string for an empty secret, so %s, %v and %q never print the value.
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return Mask
+}
+
+// GoString is a method that masks the secret for %#v.
+func (s Secret) GoString() string {
+	return `"` + s.String() + `"`
+}
+
+// MarshalJSON is a method that masks the secret in JSON, including zap fields logged with zap.Any.
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + s.String() + `"`), nil
+}
+
+// MarshalText is a method that masks the secret in text encodings like YAML.
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
@@ -0,0 +1,24 @@
+package dto
+
+import "time"
+
+// EmailWebhookEvent @Description Delivery event sent by the email provider
+type EmailWebhookEvent struct {
+	Type      string    `json:"type" validate:"required,oneof=bounce complaint delivered" example:"bounce"` // Event type: bounce, complaint or delivered
+	Email     string    `json:"email" validate:"required,email" example:"example@gmail.com"`                // Recipient address
+	MessageID string    `json:"message_id" example:"a1b2c3"`                                                // Provider message ID
+	Reason    string    `json:"reason" example:"550 mailbox does not exist"`                                // Provider supplied reason, if any
+	Timestamp time.Time `json:"timestamp" example:"2024-12-08T10:00:12Z"`                                   // Time the event happened, defaults to receive time
+}
+
+// EmailWebhookPayload @Description Batch of delivery events sent by the email provider
+type EmailWebhookPayload struct {
+	Events []EmailWebhookEvent `json:"events" validate:"required,min=1,dive"` // Delivery events
+}
+
+type EmailEventReturn struct {
+	Type       string    `json:"type" example:"bounce"`                       // Event type
+	MessageID  string    `json:"message_id" example:"a1b2c3"`                 // Provider message ID
+	Reason     string    `json:"reason" example:"550 mailbox does not exist"` // Provider supplied reason
+	OccurredAt time.Time `json:"occurred_at" example:"2024-12-08T10:00:12Z"`  // Time the event happened
+}
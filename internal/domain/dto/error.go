@@ -1,6 +1,20 @@
 package dto
 
-type HTTPError struct {
-	Code    int    `json:"code" example:"400"`               // HTTP error code
-	Message string `json:"message" example:"you are retard"` // Error message
+// Problem @Description Error response in RFC 7807 "application/problem+json" format
+type Problem struct {
+	Type      string         `json:"type" example:"urn:problem-type:validation"`                          // URI reference identifying the problem type
+	Title     string         `json:"title" example:"Bad Request"`                                         // Short summary of the problem type
+	Status    int            `json:"status" example:"400"`                                                // HTTP status code
+	Detail    string         `json:"detail,omitempty" example:"validation failed"`                        // Explanation of this occurrence of the problem
+	Instance  string         `json:"instance,omitempty" example:"/api/v1/user/login"`                     // URI reference of the request that caused the problem
+	Errors    []ProblemField `json:"errors,omitempty"`                                                    // Field-level validation problems
+	RequestID string         `json:"request_id,omitempty" example:"6c0f5ad1-6277-4110-ab51-9a7828c2a711"` // X-Request-ID of the request, to be quoted in support requests
+}
+
+// ProblemField @Description Validation problem of a single request field
+type ProblemField struct {
+	Field   string            `json:"field" example:"password"`                                      // JSON name of the invalid field
+	Rule    string            `json:"rule" example:"password"`                                       // Name of the failed validation rule
+	Message string            `json:"message" example:"password must be at least 8 characters long"` // Human-readable description of the problem
+	Params  map[string]string `json:"params,omitempty"`                                              // Params of the rule, e.g. limits
 }
@@ -0,0 +1,23 @@
+package dto
+
+import "time"
+
+const (
+	HealthOK          = "ok"
+	HealthUnavailable = "unavailable"
+)
+
+// Health @Description Health of the app and its dependencies
+type Health struct {
+	Status    string        `json:"status" example:"ok"`                                 // ok or unavailable
+	Checks    []HealthCheck `json:"checks,omitempty"`                                    // Dependency checks, only for readiness
+	CheckedAt *time.Time    `json:"checked_at,omitempty" example:"2024-12-08T10:00:00Z"` // Time of the checks, results are cached for a few seconds
+}
+
+// HealthCheck @Description Result of a dependency check
+type HealthCheck struct {
+	Name     string  `json:"name" example:"postgres"`           // the storage (postgres, sqlite or memory), email, redis or shutdown
+	Status   string  `json:"status" example:"ok"`               // ok or unavailable
+	Duration float64 `json:"duration_ms" example:"1.5"`         // Check duration in milliseconds
+	Error    string  `json:"error,omitempty" example:"timeout"` // Error of a failed check, only in debug mode
+}
@@ -0,0 +1,17 @@
+package dto
+
+import "time"
+
+// LogLevelReturn @Description Log level of an app component
+type LogLevelReturn struct {
+	Component  string     `json:"component" example:"db"`                             // Component: app, http, db, email or auth
+	Level      string     `json:"level" example:"debug"`                              // Current level
+	Configured string     `json:"configured" example:"info"`                          // Level from the config, restored at revert_at
+	RevertAt   *time.Time `json:"revert_at,omitempty" example:"2024-12-08T10:15:00Z"` // Expiration of a temporary level
+}
+
+// LogLevelUpdate @Description Temporary log level of a component
+type LogLevelUpdate struct {
+	Level    string `json:"level" validate:"required,oneof=debug info warn error" example:"debug"` // Required, debug, info, warn or error
+	Duration string `json:"duration" validate:"omitempty,duration" example:"15m"`                  // Time until the configured level is restored, 15m by default, 24h at most
+}
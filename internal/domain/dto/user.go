@@ -1,9 +1,11 @@
 package dto
 
+import "time"
+
 // UserRegister @Description User registration dto
 type UserRegister struct {
 	Email    string `json:"email" validate:"required,email" example:"example@gmail.com"`  // Required, email must be valid
-	Password string `json:"password" validate:"required,password" example:"Password1234"` // Required, password must meet certain requirements: must has upper case letters, lower case letters and digits
+	Password string `json:"password" validate:"required,password" example:"Password1234"` // Required, password must meet the password policy
 	Username string `json:"username" validate:"required,username" example:"linuxflight"`  // Required, user's username
 }
 
@@ -25,6 +27,31 @@ type UserRegisterResponse struct {
 }
 
 type UserLogin struct {
-	Email    string `json:"email" validate:"required,email" example:"example@gmail.com"`  // User's email, must be valid email address
-	Password string `json:"password" validate:"required,password" example:"Password1234"` // User's password
+	Email    string `json:"email" validate:"required,email" example:"example@gmail.com"` // User's email, must be valid email address
+	Password string `json:"password" validate:"required" example:"Password1234"`         // User's password
+}
+
+// UserChangePassword @Description User password change dto
+type UserChangePassword struct {
+	OldPassword string `json:"old_password" validate:"required" example:"Password1234"`             // Current password
+	NewPassword string `json:"new_password" validate:"required,password" example:"NewPassword1234"` // New password, must meet the password policy
+}
+
+type AdminUserReturn struct {
+	UserReturn
+	EmailDeliveryState     string             `json:"email_delivery_state" example:"bounced"`                   // Email delivery state: unknown, delivered, bounced or complained
+	EmailDeliveryUpdatedAt *time.Time         `json:"email_delivery_updated_at" example:"2024-12-08T10:00:12Z"` // Time of the last delivery state change
+	EmailEvents            []EmailEventReturn `json:"email_events,omitempty"`                                   // Latest email delivery events
+}
+
+// AvailabilityQuery @Description Email or username availability check query
+type AvailabilityQuery struct {
+	Value string `json:"value" query:"value" validate:"required,max=320"` // Email or username to check
+}
+
+// Availability @Description Result of an email or username availability check
+type Availability struct {
+	Value     string `json:"value" example:"linuxflight"`      // Checked value
+	Available bool   `json:"available" example:"false"`        // Whether the value can be used for registration
+	Reason    string `json:"reason,omitempty" example:"taken"` // Why the value can't be used: taken, reserved or invalid
 }
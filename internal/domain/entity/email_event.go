@@ -0,0 +1,23 @@
+package entity
+
+import "time"
+
+// EmailEvent is a struct that represents a delivery event reported by the email provider.
+type EmailEvent struct {
+	ID        string `gorm:"primaryKey;not null;type:uuid;default:gen_random_uuid()"`
+	CreatedAt time.Time
+
+	UserID     *string `gorm:"type:uuid;index"`
+	Email      string  `gorm:"not null;index"`
+	Type       string  `gorm:"not null"`
+	MessageID  string  `gorm:"index"`
+	Reason     string
+	OccurredAt time.Time `gorm:"not null"`
+	User       *User     `gorm:"foreignKey:user_id;references:id"`
+}
+
+const (
+	EmailEventBounce    = "bounce"
+	EmailEventComplaint = "complaint"
+	EmailEventDelivered = "delivered"
+)
@@ -0,0 +1,20 @@
+package entity
+
+import "time"
+
+// OutboxEmail is a struct that represents an email waiting in database to be sent by the outbox worker.
+type OutboxEmail struct {
+	ID        string `gorm:"primaryKey;not null;type:uuid;default:gen_random_uuid()"`
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	Email         string    `gorm:"not null"`
+	Subject       string    `gorm:"not null"`
+	Body          string    `gorm:"not null"`
+	Attempts      int       `gorm:"default:0;not null"`
+	NextAttemptAt time.Time `gorm:"not null;index"`
+	SentAt        *time.Time
+	FailedAt      *time.Time
+	LastError     string
+	RequestID     string // Id of the API request that enqueued the email, forwarded to the email provider
+}
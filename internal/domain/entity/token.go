@@ -8,7 +8,7 @@ type Token struct {
 	CreatedAt time.Time
 	UpdatedAt time.Time
 
-	Token   string    `gorm:"not null"`
+	Token   string    `gorm:"not null;index"`
 	UserID  string    `gorm:"not null;type:uuid"`
 	Type    string    `gorm:"not null"`
 	Expires time.Time `gorm:"not null"`
@@ -1,7 +1,6 @@
 package entity
 
 import (
-	"golang.org/x/crypto/bcrypt"
 	"time"
 )
 
@@ -18,25 +17,49 @@ type User struct {
 	Role             string  `json:"role" gorm:"default:user;not null"`
 	Token            []Token `json:"-" gorm:"foreignKey:user_id;references:id"`
 	Username         string  `json:"username"`
+
+	EmailCanonical    string `json:"-" gorm:"uniqueIndex"` // Lower case email, unique
+	UsernameCanonical string `json:"-" gorm:"uniqueIndex"` // NFKC-normalized lower case username, unique
+
+	EmailDeliveryState     string     `json:"email_delivery_state" gorm:"default:unknown;not null"`
+	EmailDeliveryUpdatedAt *time.Time `json:"email_delivery_updated_at"`
 }
 
-// HashedPassword is a function to hash the password.
-func HashedPassword(password string) []byte {
-	hashedPassword, _ := bcrypt.GenerateFromPassword([]byte(password), 14)
-	return hashedPassword
+// PasswordHasher is an interface of the component that hashes and verifies user passwords.
+type PasswordHasher interface {
+	Hash(password string) (string, error)
+	Verify(password, encoded string) (needsRehash bool, err error)
 }
 
 // SetPassword is a method to hash the password before storing it.
-func (user *User) SetPassword(password string) {
-	user.Password = HashedPassword(password)
+func (user *User) SetPassword(hasher PasswordHasher, password string) error {
+	hash, err := hasher.Hash(password)
+	if err != nil {
+		return err
+	}
+	user.Password = []byte(hash)
+	return nil
 }
 
 // ComparePassword is a method to compare the password with the hashed password.
-func (user *User) ComparePassword(password string) error {
-	return bcrypt.CompareHashAndPassword(user.Password, []byte(password))
+// needsRehash reports that the password matches, but the hash should be replaced using SetPassword.
+func (user *User) ComparePassword(hasher PasswordHasher, password string) (needsRehash bool, err error) {
+	return hasher.Verify(password, string(user.Password))
+}
+
+// EmailUndeliverable is a method that reports whether emails must not be sent to the user's address.
+func (user *User) EmailUndeliverable() bool {
+	return user.EmailDeliveryState == EmailDeliveryBounced || user.EmailDeliveryState == EmailDeliveryComplained
 }
 
 const (
-	userRole  = "user"
-	adminRole = "admin"
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+const (
+	EmailDeliveryUnknown    = "unknown"
+	EmailDeliveryDelivered  = "delivered"
+	EmailDeliveryBounced    = "bounced"
+	EmailDeliveryComplained = "complained"
 )
@@ -9,17 +9,30 @@ import (
 	"io"
 	"mime/multipart"
 	"net/http"
+	"time"
 	"webTemplate/internal/adapters/config"
+	"webTemplate/internal/domain/common/errorz"
+	"webTemplate/internal/domain/common/requestid"
+	"webTemplate/internal/domain/entity"
 )
 
+// httpClient is the client of the https://maileroo.com API, the timeout bounds requests to a stalled connection,
+// e.g. of the outbox worker that sends without the cancellation of its context.
+var httpClient = &http.Client{Timeout: 15 * time.Second}
+
 type EmailApi interface {
 	Send(ctx context.Context, email string, text string, subject string) error
 	Check(ctx context.Context, email string) (bool, error)
 }
 
+type RecipientStorage interface {
+	GetByEmail(ctx context.Context, email string) (*entity.User, error)
+}
+
 type emailService struct {
-	service EmailApi
-	config  config.MailerooConfig
+	service    EmailApi
+	config     config.MailerooConfig
+	recipients RecipientStorage
 }
 
 type sendResponse struct {
@@ -42,14 +55,20 @@ type checkResponse struct {
 	} `json:"data"`
 }
 
-func NewEmailService(config config.MailerooConfig) *emailService {
+func NewEmailService(config config.MailerooConfig, recipients RecipientStorage) *emailService {
 	return &emailService{
-		config: config,
+		config:     config,
+		recipients: recipients,
 	}
 }
 
-// Send is a method to send email using https://maileroo.com API
+// Send is a method to send email using https://maileroo.com API.
+// Addresses marked as undeliverable by provider webhooks are skipped with errorz.EmailUndeliverable.
 func (s *emailService) Send(ctx context.Context, email string, text string, subject string) error {
+	if user, err := s.recipients.GetByEmail(ctx, email); err == nil && user.EmailUndeliverable() {
+		return errorz.EmailUndeliverable
+	}
+
 	// request payload
 	payload := &bytes.Buffer{}
 	writer := multipart.NewWriter(payload)
@@ -60,11 +79,14 @@ func (s *emailService) Send(ctx context.Context, email string, text string, subj
 	_ = writer.Close()
 
 	// send http request
-	client := &http.Client{}
-	req, _ := http.NewRequest("POST", "https://smtp.maileroo.com/send", payload)
-	req.Header.Set("X-API-Key", s.config.SendingApiKey)
+	req, reqErr := http.NewRequestWithContext(ctx, http.MethodPost, "https://smtp.maileroo.com/send", payload)
+	if reqErr != nil {
+		return reqErr
+	}
+	req.Header.Set("X-API-Key", s.config.SendingApiKey.Reveal())
 	req.Header.Set("Content-Type", writer.FormDataContentType())
-	res, respErr := client.Do(req)
+	setRequestID(ctx, req)
+	res, respErr := httpClient.Do(req)
 	if respErr != nil {
 		return respErr
 	}
@@ -93,6 +115,24 @@ func (s *emailService) Send(ctx context.Context, email string, text string, subj
 	return nil
 }
 
+// Ping is a method that checks that the https://maileroo.com API is reachable, responses other than server errors count.
+func (s *emailService) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, "https://smtp.maileroo.com/", nil)
+	if err != nil {
+		return err
+	}
+	res, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	_ = res.Body.Close()
+
+	if res.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("email provider responded with %s", res.Status)
+	}
+	return nil
+}
+
 // Check is a method to check via https://maileroo.com API the email address of a user
 func (s *emailService) Check(ctx context.Context, email string) (bool, error) {
 	// send http api request
@@ -100,11 +140,14 @@ func (s *emailService) Check(ctx context.Context, email string) (bool, error) {
 		"email_address": email,
 	}
 	jsonValue, _ := json.Marshal(requestData)
-	req, _ := http.NewRequest("POST", "https://verify.maileroo.net/check", bytes.NewBuffer(jsonValue))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://verify.maileroo.net/check", bytes.NewBuffer(jsonValue))
+	if err != nil {
+		return false, err
+	}
 	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-API-Key", s.config.VerificationApiKey)
-	client := &http.Client{}
-	response, err := client.Do(req)
+	req.Header.Set("X-API-Key", s.config.VerificationApiKey.Reveal())
+	setRequestID(ctx, req)
+	response, err := httpClient.Do(req)
 	if err != nil {
 		return false, err
 	}
@@ -137,3 +180,10 @@ func (s *emailService) Check(ctx context.Context, email string) (bool, error) {
 
 	return true, nil
 }
+
+// setRequestID is a function that forwards the id of the API request that caused the call to the provider.
+func setRequestID(ctx context.Context, req *http.Request) {
+	if id := requestid.FromContext(ctx); id != "" {
+		req.Header.Set(requestid.Header, id)
+	}
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	"webTemplate/internal/domain/common/errorz"
+	"webTemplate/internal/domain/dto"
+	"webTemplate/internal/domain/entity"
+)
+
+type EmailEventStorage interface {
+	Create(ctx context.Context, event entity.EmailEvent) (*entity.EmailEvent, error)
+	GetByUserID(ctx context.Context, userID string, limit int) ([]entity.EmailEvent, error)
+}
+
+// Transactor is an interface of the component running functions in a database transaction,
+// storages called with the context passed to fn use it.
+type Transactor interface {
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// emailEventService is a struct that contains storages to record email delivery events.
+type emailEventService struct {
+	transactor  Transactor
+	storage     EmailEventStorage
+	userStorage UserStorage
+}
+
+func NewEmailEventService(transactor Transactor, storage EmailEventStorage, userStorage UserStorage) *emailEventService {
+	return &emailEventService{
+		transactor:  transactor,
+		storage:     storage,
+		userStorage: userStorage,
+	}
+}
+
+// HandleAll is a method to persist a batch of delivery events, either all of them or, on any error, none.
+// The provider retries failed batches as a whole, so events of a failed batch must not be recorded twice.
+func (s *emailEventService) HandleAll(ctx context.Context, events []dto.EmailWebhookEvent) error {
+	return s.transactor.Do(ctx, func(ctx context.Context) error {
+		for _, event := range events {
+			if err := s.Handle(ctx, event); err != nil {
+				return fmt.Errorf("failed to handle email event %s for %s: %w", event.Type, event.Email, err)
+			}
+		}
+		return nil
+	})
+}
+
+// Handle is a method to persist a delivery event and update the delivery state of the recipient.
+func (s *emailEventService) Handle(ctx context.Context, event dto.EmailWebhookEvent) error {
+	occurredAt := event.Timestamp.UTC()
+	if event.Timestamp.IsZero() {
+		occurredAt = time.Now().UTC()
+	}
+
+	user, err := s.userStorage.GetByEmail(ctx, event.Email)
+	if err != nil && !errors.Is(err, errorz.UserNotFound) {
+		return err
+	}
+
+	record := entity.EmailEvent{
+		Email:      event.Email,
+		Type:       event.Type,
+		MessageID:  event.MessageID,
+		Reason:     event.Reason,
+		OccurredAt: occurredAt,
+	}
+	if user != nil && user.ID != "" {
+		record.UserID = &user.ID
+	}
+	if _, err = s.storage.Create(ctx, record); err != nil {
+		return err
+	}
+
+	if record.UserID == nil {
+		return nil
+	}
+
+	state, changed := nextDeliveryState(user, event.Type, occurredAt)
+	if !changed {
+		return nil
+	}
+	user.EmailDeliveryState = state
+	user.EmailDeliveryUpdatedAt = &occurredAt
+	_, err = s.userStorage.Update(ctx, user)
+	return err
+}
+
+// GetByUserID is a method that returns the latest delivery events of a user.
+func (s *emailEventService) GetByUserID(ctx context.Context, userID string, limit int) ([]entity.EmailEvent, error) {
+	return s.storage.GetByUserID(ctx, userID, limit)
+}
+
+// nextDeliveryState is a function that returns the delivery state the user moves to after the event.
+// Events older than the current state are ignored, a complaint is never cleared by a later delivery.
+func nextDeliveryState(user *entity.User, eventType string, occurredAt time.Time) (string, bool) {
+	if user.EmailDeliveryUpdatedAt != nil && occurredAt.Before(*user.EmailDeliveryUpdatedAt) {
+		return user.EmailDeliveryState, false
+	}
+
+	switch eventType {
+	case entity.EmailEventBounce:
+		if user.EmailDeliveryState == entity.EmailDeliveryComplained {
+			return user.EmailDeliveryState, false
+		}
+		return entity.EmailDeliveryBounced, true
+	case entity.EmailEventComplaint:
+		return entity.EmailDeliveryComplained, true
+	case entity.EmailEventDelivered:
+		if user.EmailDeliveryState == entity.EmailDeliveryComplained {
+			return user.EmailDeliveryState, false
+		}
+		return entity.EmailDeliveryDelivered, true
+	default:
+		return user.EmailDeliveryState, false
+	}
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"webTemplate/internal/adapters/database/memory"
+	"webTemplate/internal/domain/dto"
+	"webTemplate/internal/domain/entity"
+	"webTemplate/internal/domain/utils/username"
+)
+
+// failingEmailEventStorage is a struct that fails to store events with the message id in fail.
+type failingEmailEventStorage struct {
+	EmailEventStorage
+	fail string
+}
+
+func (s *failingEmailEventStorage) Create(ctx context.Context, event entity.EmailEvent) (*entity.EmailEvent, error) {
+	if event.MessageID == s.fail {
+		return nil, errors.New("storage is down")
+	}
+	return s.EmailEventStorage.Create(ctx, event)
+}
+
+func TestHandleAllRetriedBatch(t *testing.T) {
+	ctx := context.Background()
+	users := memory.NewUserStorage()
+	events := memory.NewEmailEventStorage()
+	storage := &failingEmailEventStorage{EmailEventStorage: events, fail: "m3"}
+	emailEvents := NewEmailEventService(memory.NewTransactionManager(users, events), storage, users)
+
+	user, err := users.Create(ctx, entity.User{
+		Email:             "retried@example.com",
+		Username:          "retried",
+		EmailCanonical:    username.CanonicalEmail("retried@example.com"),
+		UsernameCanonical: username.Canonical("retried"),
+	})
+	if err != nil {
+		t.Fatalf("Create user: %v", err)
+	}
+
+	now := time.Now().UTC()
+	batch := []dto.EmailWebhookEvent{
+		{Type: entity.EmailEventDelivered, Email: user.Email, MessageID: "m1", Timestamp: now.Add(-2 * time.Minute)},
+		{Type: entity.EmailEventBounce, Email: user.Email, MessageID: "m2", Timestamp: now.Add(-time.Minute)},
+		{Type: entity.EmailEventDelivered, Email: user.Email, MessageID: "m3", Timestamp: now},
+	}
+	if err = emailEvents.HandleAll(ctx, batch); err == nil {
+		t.Fatal("HandleAll with a failing event: error is nil")
+	}
+
+	stored, _ := events.GetByUserID(ctx, user.ID, 10)
+	if len(stored) != 0 {
+		t.Errorf("events after a failed batch = %+v, want none", stored)
+	}
+	if got, _ := users.GetByID(ctx, user.ID); got.EmailDeliveryState != entity.EmailDeliveryUnknown {
+		t.Errorf("delivery state after a failed batch = %q, want %q", got.EmailDeliveryState, entity.EmailDeliveryUnknown)
+	}
+
+	// The provider retries the whole batch
+	storage.fail = ""
+	if err = emailEvents.HandleAll(ctx, batch); err != nil {
+		t.Fatalf("HandleAll retry: %v", err)
+	}
+	stored, _ = events.GetByUserID(ctx, user.ID, 10)
+	if len(stored) != len(batch) {
+		t.Errorf("events after the retry = %d, want %d", len(stored), len(batch))
+	}
+	if got, _ := users.GetByID(ctx, user.ID); got.EmailDeliveryState != entity.EmailDeliveryDelivered {
+		t.Errorf("delivery state after the retry = %q, want %q", got.EmailDeliveryState, entity.EmailDeliveryDelivered)
+	}
+}
+
+func TestNextDeliveryState(t *testing.T) {
+	updatedAt := time.Date(2024, time.March, 1, 12, 0, 0, 0, time.UTC)
+	before := updatedAt.Add(-time.Minute)
+	after := updatedAt.Add(time.Minute)
+
+	tests := []struct {
+		name        string
+		state       string
+		updatedAt   *time.Time
+		eventType   string
+		occurredAt  time.Time
+		wantState   string
+		wantChanged bool
+	}{
+		{"first delivered", entity.EmailDeliveryUnknown, nil, entity.EmailEventDelivered, after, entity.EmailDeliveryDelivered, true},
+		{"first bounce", entity.EmailDeliveryUnknown, nil, entity.EmailEventBounce, after, entity.EmailDeliveryBounced, true},
+		{"first complaint", entity.EmailDeliveryUnknown, nil, entity.EmailEventComplaint, after, entity.EmailDeliveryComplained, true},
+		{"bounce over delivered", entity.EmailDeliveryDelivered, &updatedAt, entity.EmailEventBounce, after, entity.EmailDeliveryBounced, true},
+		{"complaint over bounce", entity.EmailDeliveryBounced, &updatedAt, entity.EmailEventComplaint, after, entity.EmailDeliveryComplained, true},
+		{"complaint over delivered", entity.EmailDeliveryDelivered, &updatedAt, entity.EmailEventComplaint, after, entity.EmailDeliveryComplained, true},
+		{"delivered after bounce", entity.EmailDeliveryBounced, &updatedAt, entity.EmailEventDelivered, after, entity.EmailDeliveryDelivered, true},
+		{"bounce keeps complaint", entity.EmailDeliveryComplained, &updatedAt, entity.EmailEventBounce, after, entity.EmailDeliveryComplained, false},
+		{"delivered keeps complaint", entity.EmailDeliveryComplained, &updatedAt, entity.EmailEventDelivered, after, entity.EmailDeliveryComplained, false},
+		{"older complaint ignored", entity.EmailDeliveryDelivered, &updatedAt, entity.EmailEventComplaint, before, entity.EmailDeliveryDelivered, false},
+		{"older delivered ignored", entity.EmailDeliveryBounced, &updatedAt, entity.EmailEventDelivered, before, entity.EmailDeliveryBounced, false},
+		{"same instant applied", entity.EmailDeliveryDelivered, &updatedAt, entity.EmailEventBounce, updatedAt, entity.EmailDeliveryBounced, true},
+		{"unknown event ignored", entity.EmailDeliveryDelivered, &updatedAt, "opened", after, entity.EmailDeliveryDelivered, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &entity.User{EmailDeliveryState: tt.state, EmailDeliveryUpdatedAt: tt.updatedAt}
+			state, changed := nextDeliveryState(user, tt.eventType, tt.occurredAt)
+			if state != tt.wantState || changed != tt.wantChanged {
+				t.Errorf("nextDeliveryState() = (%q, %t), want (%q, %t)", state, changed, tt.wantState, tt.wantChanged)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+	"webTemplate/internal/domain/dto"
+)
+
+// HealthCheck is a struct that contains a named check of a dependency, e.g. a database ping.
+type HealthCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// HealthConfig is a struct that contains the readiness check settings.
+type HealthConfig struct {
+	Timeout  time.Duration // Timeout of a single check
+	CacheTTL time.Duration // Time the results are reused, so frequent probes don't load the dependencies
+}
+
+// healthService is a struct that runs the dependency checks and caches their results.
+type healthService struct {
+	checks       []HealthCheck
+	config       HealthConfig
+	shuttingDown func() bool
+
+	mu       sync.Mutex
+	cached   dto.Health
+	cachedAt time.Time
+}
+
+// NewHealthService is a function that creates a health service
+/*
+ * checks []HealthCheck - dependency checks, run in parallel
+ * config HealthConfig - check timeout and cache ttl
+ * shuttingDown func() bool - reports a graceful shutdown, the app is not ready during it
+ */
+func NewHealthService(checks []HealthCheck, config HealthConfig, shuttingDown func() bool) *healthService {
+	return &healthService{
+		checks:       checks,
+		config:       config,
+		shuttingDown: shuttingDown,
+	}
+}
+
+// Ready is a method that reports whether the app can serve requests: it is not shutting down and all checks pass.
+// Results younger than the cache ttl are reused, concurrent calls wait for a single run of the checks.
+func (s *healthService) Ready(ctx context.Context) dto.Health {
+	if s.shuttingDown() {
+		now := time.Now().UTC()
+		return dto.Health{
+			Status:    dto.HealthUnavailable,
+			Checks:    []dto.HealthCheck{{Name: "shutdown", Status: dto.HealthUnavailable, Error: "the app is shutting down"}},
+			CheckedAt: &now,
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.cachedAt.IsZero() && time.Since(s.cachedAt) < s.config.CacheTTL {
+		return s.cached
+	}
+
+	s.cached = s.run(ctx)
+	s.cachedAt = time.Now()
+	return s.cached
+}
+
+// run is a method that runs all checks in parallel, each with its own timeout.
+func (s *healthService) run(ctx context.Context) dto.Health {
+	results := make([]dto.HealthCheck, len(s.checks))
+
+	var wg sync.WaitGroup
+	for i, check := range s.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = s.runCheck(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	now := time.Now().UTC()
+	health := dto.Health{Status: dto.HealthOK, Checks: results, CheckedAt: &now}
+	for _, result := range results {
+		if result.Status != dto.HealthOK {
+			health.Status = dto.HealthUnavailable
+		}
+	}
+	return health
+}
+
+// runCheck is a method that runs a check with the check timeout.
+// The check is detached from the request context, a cancelled probe must not fail the cached result.
+func (s *healthService) runCheck(ctx context.Context, check HealthCheck) dto.HealthCheck {
+	checkCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.config.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check.Check(checkCtx)
+	result := dto.HealthCheck{
+		Name:     check.Name,
+		Status:   dto.HealthOK,
+		Duration: float64(time.Since(start)) / float64(time.Millisecond),
+	}
+	if err != nil {
+		result.Status = dto.HealthUnavailable
+		result.Error = err.Error()
+	}
+	return result
+}
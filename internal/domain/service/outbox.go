@@ -0,0 +1,130 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+	"webTemplate/internal/adapters/logger"
+	"webTemplate/internal/domain/common/errorz"
+	"webTemplate/internal/domain/common/requestid"
+	"webTemplate/internal/domain/entity"
+)
+
+type OutboxStorage interface {
+	Create(ctx context.Context, email entity.OutboxEmail) (*entity.OutboxEmail, error)
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]entity.OutboxEmail, error)
+	MarkSent(ctx context.Context, id string) error
+	MarkFailed(ctx context.Context, id string, lastError string, nextAttemptAt *time.Time) error
+}
+
+type EmailSender interface {
+	Send(ctx context.Context, email string, text string, subject string) error
+}
+
+// outboxService is a struct that contains a storage to enqueue emails, so they are stored together with the data they belong to.
+type outboxService struct {
+	storage OutboxStorage
+}
+
+func NewOutboxService(storage OutboxStorage) *outboxService {
+	return &outboxService{storage: storage}
+}
+
+// Enqueue is a method to store an email in the outbox, the outbox worker sends it later.
+func (s *outboxService) Enqueue(ctx context.Context, email string, text string, subject string) error {
+	_, err := s.storage.Create(ctx, entity.OutboxEmail{
+		Email:         email,
+		Subject:       subject,
+		Body:          text,
+		NextAttemptAt: time.Now().UTC(),
+		RequestID:     requestid.FromContext(ctx),
+	})
+	return err
+}
+
+// OutboxWorkerConfig is a struct that contains the outbox worker settings.
+type OutboxWorkerConfig struct {
+	PollInterval time.Duration // Delay between outbox polls
+	BatchSize    int           // Maximum emails claimed per poll
+	MaxAttempts  int           // Attempts before an email is marked as failed permanently
+	Lease        time.Duration // Time other workers skip a claimed email
+}
+
+// outboxWorker is a struct that periodically sends emails stored in the outbox.
+type outboxWorker struct {
+	storage OutboxStorage
+	sender  EmailSender
+	config  OutboxWorkerConfig
+}
+
+func NewOutboxWorker(storage OutboxStorage, sender EmailSender, config OutboxWorkerConfig) *outboxWorker {
+	return &outboxWorker{
+		storage: storage,
+		sender:  sender,
+		config:  config,
+	}
+}
+
+// Run is a method that polls the outbox until the context is cancelled.
+// The email being sent when the context is cancelled is finished and marked, the rest of its batch is left
+// to be claimed again when the lease expires.
+func (w *outboxWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		w.process(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// process is a method to send one batch of due emails, it stops before the next email once stop is cancelled.
+func (w *outboxWorker) process(stop context.Context) {
+	log := logger.Log.Named(logger.ComponentEmail)
+	// A started send and its marking are finished, so a sent email isn't sent again
+	ctx := context.WithoutCancel(stop)
+
+	emails, err := w.storage.Claim(ctx, w.config.BatchSize, w.config.Lease)
+	if err != nil {
+		log.Errorf("failed to claim outbox emails: %v", err)
+		return
+	}
+
+	for _, email := range emails {
+		if stop.Err() != nil {
+			return
+		}
+		sendErr := w.sender.Send(requestid.WithContext(ctx, email.RequestID), email.Email, email.Body, email.Subject)
+		if sendErr == nil {
+			if err = w.storage.MarkSent(ctx, email.ID); err != nil {
+				log.Errorf("failed to mark outbox email %s as sent: %v", email.ID, err)
+			}
+			log.Debugf("sent outbox email %s (request %s)", email.ID, email.RequestID)
+			continue
+		}
+
+		var nextAttemptAt *time.Time
+		if !errors.Is(sendErr, errorz.EmailUndeliverable) && email.Attempts+1 < w.config.MaxAttempts {
+			next := time.Now().UTC().Add(outboxBackoff(email.Attempts))
+			nextAttemptAt = &next
+		}
+		log.Warnf("failed to send outbox email %s (attempt %d): %v", email.ID, email.Attempts+1, sendErr)
+		if err = w.storage.MarkFailed(ctx, email.ID, sendErr.Error(), nextAttemptAt); err != nil {
+			log.Errorf("failed to mark outbox email %s as failed: %v", email.ID, err)
+		}
+	}
+}
+
+// outboxBackoff is a function that returns the delay before the next attempt: 30s doubled per attempt, up to one hour.
+func outboxBackoff(attempts int) time.Duration {
+	delay := 30 * time.Second
+	for i := 0; i < attempts && delay < time.Hour; i++ {
+		delay *= 2
+	}
+	return min(delay, time.Hour)
+}
@@ -2,8 +2,8 @@ package service
 
 import (
 	"context"
-	"github.com/spf13/viper"
 	"time"
+	"webTemplate/internal/adapters/config"
 	"webTemplate/internal/domain/dto"
 	"webTemplate/internal/domain/entity"
 	"webTemplate/internal/domain/utils/auth"
@@ -12,22 +12,27 @@ import (
 type TokenStorage interface {
 	Create(ctx context.Context, token entity.Token) (*entity.Token, error)
 	GetByUserID(ctx context.Context, userID string, tokenType string) (*entity.Token, error)
+	GetByToken(ctx context.Context, token string, tokenType string) (*entity.Token, error)
 	DeleteAll(ctx context.Context, userID string) error
 	Delete(ctx context.Context, userID string, tokenType string) error
 }
 
-// tokenService is a struct that contains a pointer to a gorm.DB instance to interact with token repository.
+// tokenService is a struct that contains a token storage and the jwt settings used to sign and verify tokens.
 type tokenService struct {
 	storage TokenStorage
+	config  config.JWTConfig
 }
 
-func NewTokenService(storage TokenStorage) *tokenService {
-	return &tokenService{storage: storage}
+func NewTokenService(storage TokenStorage, config config.JWTConfig) *tokenService {
+	return &tokenService{
+		storage: storage,
+		config:  config,
+	}
 }
 
 // GenerateToken is a method to generate a new token.
 func (s *tokenService) GenerateToken(ctx context.Context, userID string, expires time.Time, tokenType string) (*entity.Token, error) {
-	jwtToken, err := auth.GenerateToken(userID, expires, tokenType)
+	jwtToken, err := auth.GenerateToken(userID, expires, tokenType, s.config.Secret.Reveal())
 	if err != nil {
 		return nil, err
 	}
@@ -50,14 +55,24 @@ func (s *tokenService) DeleteToken(ctx context.Context, userID string, tokenType
 	return s.storage.Delete(ctx, userID, tokenType)
 }
 
+// GetByToken is a method to get a stored, not expired token by token string and token type.
+func (s *tokenService) GetByToken(ctx context.Context, token string, tokenType string) (*entity.Token, error) {
+	return s.storage.GetByToken(ctx, token, tokenType)
+}
+
+// GenerateAccessToken is a method to generate a new access token with the configured expiration.
+func (s *tokenService) GenerateAccessToken(ctx context.Context, userID string) (*entity.Token, error) {
+	return s.GenerateToken(ctx, userID, time.Now().UTC().Add(s.config.AccessTokenExpiration), auth.TokenTypeAccess)
+}
+
+// VerifyToken is a method to check the signature and the type of a token, it returns the id of the token owner.
+func (s *tokenService) VerifyToken(token string, tokenType string) (string, error) {
+	return auth.VerifyToken(token, s.config.Secret.Reveal(), tokenType)
+}
+
 // GenerateAuthTokens is a method to generate access and refresh tokens.
 func (s *tokenService) GenerateAuthTokens(c context.Context, userID string) (*dto.AuthTokens, error) {
-	authToken, err := s.GenerateToken(
-		c,
-		userID,
-		time.Now().UTC().Add(time.Minute*time.Duration(viper.GetInt("service.backend.jwt.access-token-expiration"))),
-		auth.TokenTypeAccess,
-	)
+	authToken, err := s.GenerateAccessToken(c, userID)
 	if err != nil {
 		return nil, err
 	}
@@ -65,7 +80,7 @@ func (s *tokenService) GenerateAuthTokens(c context.Context, userID string) (*dt
 	refreshToken, err := s.GenerateToken(
 		c,
 		userID,
-		time.Now().UTC().Add(time.Minute*time.Duration(viper.GetInt("service.backend.jwt.refresh-token-expiration"))),
+		time.Now().UTC().Add(s.config.RefreshTokenExpiration),
 		auth.TokenTypeRefresh,
 	)
 	if err != nil {
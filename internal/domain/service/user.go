@@ -2,42 +2,70 @@ package service
 
 import (
 	"context"
+	"errors"
 	"webTemplate/internal/domain/common/errorz"
-	"webTemplate/internal/domain/dto"
 	"webTemplate/internal/domain/entity"
+	"webTemplate/internal/domain/utils/username"
 )
 
-type userStorage interface {
+type UserStorage interface {
 	Create(ctx context.Context, user entity.User) (*entity.User, error)
 	GetByID(ctx context.Context, id string) (*entity.User, error)
 	GetAll(ctx context.Context, limit, offset int) ([]entity.User, error)
 	Update(ctx context.Context, user *entity.User) (*entity.User, error)
 	Delete(ctx context.Context, id string) error
 	GetByEmail(ctx context.Context, email string) (*entity.User, error)
+	GetByUsername(ctx context.Context, username string) (*entity.User, error)
 }
 
 type userService struct {
-	storage userStorage
+	storage UserStorage
 }
 
-func NewUserService(storage userStorage) *userService {
+func NewUserService(storage UserStorage) *userService {
 	return &userService{storage: storage}
 }
 
-func (s *userService) Create(ctx context.Context, registerReq dto.UserRegister, code string) (*entity.User, error) {
-	if _, err := s.storage.GetByEmail(ctx, registerReq.Email); err == nil {
-		return nil, errorz.EmailAlreadyExists
+// Create is a method to store a new user with an already hashed password, it fails if the email or the username is taken.
+// Emails and usernames are compared case-insensitively, their canonical forms are stored with the user.
+func (s *userService) Create(ctx context.Context, user entity.User) (*entity.User, error) {
+	user.Username = username.Normalize(user.Username)
+	user.UsernameCanonical = username.Canonical(user.Username)
+	user.EmailCanonical = username.CanonicalEmail(user.Email)
+
+	if available, err := s.EmailAvailable(ctx, user.Email); err != nil {
+		return nil, err
+	} else if !available {
+		return nil, errorz.EmailAlreadyTaken
 	}
 
-	user := entity.User{
-		Email:            registerReq.Email,
-		Username:         registerReq.Username,
-		VerificationCode: code,
+	if available, err := s.UsernameAvailable(ctx, user.Username); err != nil {
+		return nil, err
+	} else if !available {
+		return nil, errorz.UsernameAlreadyTaken
 	}
-	user.SetPassword(registerReq.Password)
+
 	return s.storage.Create(ctx, user)
 }
 
+// EmailAvailable is a method that reports whether no user has the email, ignoring case.
+func (s *userService) EmailAvailable(ctx context.Context, email string) (bool, error) {
+	_, err := s.storage.GetByEmail(ctx, email)
+	if errors.Is(err, errorz.UserNotFound) {
+		return true, nil
+	}
+	return false, err
+}
+
+// UsernameAvailable is a method that reports whether no user has the username, ignoring case and Unicode compatibility forms.
+func (s *userService) UsernameAvailable(ctx context.Context, name string) (bool, error) {
+	_, err := s.storage.GetByUsername(ctx, name)
+	if errors.Is(err, errorz.UserNotFound) {
+		return true, nil
+	}
+	return false, err
+}
+
 func (s *userService) GetByEmail(ctx context.Context, email string) (*entity.User, error) {
 	return s.storage.GetByEmail(ctx, email)
 }
@@ -49,3 +77,7 @@ func (s *userService) GetByID(ctx context.Context, id string) (*entity.User, err
 func (s *userService) Update(ctx context.Context, user *entity.User) (*entity.User, error) {
 	return s.storage.Update(ctx, user)
 }
+
+func (s *userService) GetAll(ctx context.Context, limit, offset int) ([]entity.User, error) {
+	return s.storage.GetAll(ctx, limit, offset)
+}
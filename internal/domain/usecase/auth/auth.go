@@ -2,23 +2,249 @@ package auth
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"time"
+	"webTemplate/internal/adapters/logger"
+	"webTemplate/internal/domain/common/errorz"
 	"webTemplate/internal/domain/dto"
 	"webTemplate/internal/domain/entity"
+	"webTemplate/internal/domain/utils/auth"
+	"webTemplate/internal/domain/utils/password"
 )
 
 type TokenService interface {
 	GenerateToken(ctx context.Context, userID string, expires time.Time, tokenType string) (*entity.Token, error)
+	GenerateAccessToken(ctx context.Context, userID string) (*entity.Token, error)
+	VerifyToken(token string, tokenType string) (string, error)
 	DeleteToken(ctx context.Context, userID string, tokenType string) error
 	GenerateAuthTokens(c context.Context, userID string) (*dto.AuthTokens, error)
+	GetByToken(ctx context.Context, token string, tokenType string) (*entity.Token, error)
 }
 
 type UserService interface {
-	GetByEmail(ctx context.Context, id string) (*entity.User, error)
-	Create(ctx context.Context, registerReq dto.UserRegister) (*entity.User, error)
+	Create(ctx context.Context, user entity.User) (*entity.User, error)
+	GetByEmail(ctx context.Context, email string) (*entity.User, error)
+	GetByID(ctx context.Context, id string) (*entity.User, error)
+	Update(ctx context.Context, user *entity.User) (*entity.User, error)
 }
 
-type authUseсase struct {
-	tokenService TokenService
-	userService  UserService
+type OutboxService interface {
+	Enqueue(ctx context.Context, email string, text string, subject string) error
+}
+
+// Transactor is an interface of the component running functions in a database transaction,
+// storages called with the context passed to fn use it.
+type Transactor interface {
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type EmailChecker interface {
+	Check(ctx context.Context, email string) (bool, error)
+}
+
+type PasswordPolicy interface {
+	Validate(password string, personal ...string) error
+}
+
+// authUsecase is a struct that contains the business logic of user registration and authentication.
+type authUsecase struct {
+	transactor     Transactor
+	tokenService   TokenService
+	userService    UserService
+	outboxService  OutboxService
+	emailChecker   EmailChecker
+	passwordPolicy PasswordPolicy
+	passwordHasher entity.PasswordHasher
+}
+
+func New(
+	transactor Transactor,
+	userService UserService,
+	tokenService TokenService,
+	outboxService OutboxService,
+	emailChecker EmailChecker,
+	passwordPolicy PasswordPolicy,
+	passwordHasher entity.PasswordHasher,
+) *authUsecase {
+	return &authUsecase{
+		transactor:     transactor,
+		tokenService:   tokenService,
+		userService:    userService,
+		outboxService:  outboxService,
+		emailChecker:   emailChecker,
+		passwordPolicy: passwordPolicy,
+		passwordHasher: passwordHasher,
+	}
+}
+
+// Register is a method to create a user together with its auth tokens and verification email.
+// Either all of them are stored or, on any error, none of them.
+func (u *authUsecase) Register(ctx context.Context, registerReq dto.UserRegister) (*dto.UserRegisterResponse, error) {
+	if err := u.passwordPolicy.Validate(registerReq.Password, registerReq.Email, registerReq.Username); err != nil {
+		return nil, err
+	}
+
+	mailValid, mvErr := u.emailChecker.Check(ctx, registerReq.Email)
+	if mvErr != nil || !mailValid {
+		logger.FromContext(ctx).Named(logger.ComponentAuth).Errorf("invalid email: %s", registerReq.Email)
+		return nil, errorz.InvalidEmail.Wrap(mvErr)
+	}
+
+	user := &entity.User{
+		Email:            registerReq.Email,
+		Username:         registerReq.Username,
+		VerificationCode: auth.GenerateCode(),
+	}
+	if err := user.SetPassword(u.passwordHasher, registerReq.Password); err != nil {
+		return nil, errorz.Internal(err)
+	}
+
+	var tokens *dto.AuthTokens
+
+	txErr := u.transactor.Do(ctx, func(ctx context.Context) error {
+		var err error
+		if user, err = u.userService.Create(ctx, *user); err != nil {
+			return err
+		}
+
+		if tokens, err = u.tokenService.GenerateAuthTokens(ctx, user.ID); err != nil {
+			return err
+		}
+
+		return u.outboxService.Enqueue(ctx, user.Email, fmt.Sprintf("Your code is: <b>%s</b>", user.VerificationCode), "Verification Code")
+	})
+	if txErr != nil {
+		return nil, txErr
+	}
+
+	return userResponse(user, tokens), nil
+}
+
+// Login is a method to check user credentials and issue a new pair of auth tokens.
+func (u *authUsecase) Login(ctx context.Context, loginReq dto.UserLogin) (*dto.UserRegisterResponse, error) {
+	user, errFetch := u.userService.GetByEmail(ctx, loginReq.Email)
+	if errors.Is(errFetch, errorz.UserNotFound) {
+		return nil, errorz.InvalidCredentials
+	}
+	if errFetch != nil {
+		return nil, errFetch
+	}
+
+	needsRehash, errCompare := user.ComparePassword(u.passwordHasher, loginReq.Password)
+	if errors.Is(errCompare, password.ErrMismatch) {
+		return nil, errorz.InvalidCredentials
+	}
+	if errCompare != nil {
+		return nil, errorz.Internal(errCompare)
+	}
+	if needsRehash {
+		u.rehashPassword(ctx, user, loginReq.Password)
+	}
+
+	tokens, err := u.tokenService.GenerateAuthTokens(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return userResponse(user, tokens), nil
+}
+
+// Refresh is a method to issue a new access token for a valid refresh token, that has not been revoked by Logout.
+func (u *authUsecase) Refresh(ctx context.Context, refreshToken string) (*dto.Token, error) {
+	userID, errToken := u.tokenService.VerifyToken(refreshToken, auth.TokenTypeRefresh)
+	if errToken != nil {
+		return nil, errToken
+	}
+
+	stored, errFetch := u.tokenService.GetByToken(ctx, refreshToken, auth.TokenTypeRefresh)
+	if errors.Is(errFetch, errorz.TokenNotFound) || (errFetch == nil && stored.UserID != userID) {
+		return nil, errorz.InvalidToken.Wrap(errFetch)
+	}
+	if errFetch != nil {
+		return nil, errFetch
+	}
+
+	newAccess, err := u.tokenService.GenerateAccessToken(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.Token{
+		Token:   newAccess.Token,
+		Expires: newAccess.Expires,
+	}, nil
+}
+
+// Verify is a method to confirm the user's email with the code sent on registration.
+func (u *authUsecase) Verify(ctx context.Context, user *entity.User, code string) error {
+	if user.VerificationCode == "NULL" {
+		return errorz.AlreadyVerified
+	}
+
+	if user.VerificationCode != code {
+		return errorz.InvalidCode
+	}
+
+	user.VerificationCode = "NULL"
+	user.VerifiedEmail = true
+	_, err := u.userService.Update(ctx, user)
+	return err
+}
+
+// ChangePassword is a method to replace the user's password after checking the current one and the password policy.
+func (u *authUsecase) ChangePassword(ctx context.Context, user *entity.User, changeReq dto.UserChangePassword) error {
+	_, errCompare := user.ComparePassword(u.passwordHasher, changeReq.OldPassword)
+	if errors.Is(errCompare, password.ErrMismatch) {
+		return errorz.InvalidCredentials
+	}
+	if errCompare != nil {
+		return errorz.Internal(errCompare)
+	}
+
+	if err := u.passwordPolicy.Validate(changeReq.NewPassword, user.Email, user.Username); err != nil {
+		return err
+	}
+
+	if err := user.SetPassword(u.passwordHasher, changeReq.NewPassword); err != nil {
+		return errorz.Internal(err)
+	}
+	_, err := u.userService.Update(ctx, user)
+	return err
+}
+
+// rehashPassword is a method to replace an outdated password hash after a successful login.
+// Failures are only logged, the user can still log in with the old hash.
+func (u *authUsecase) rehashPassword(ctx context.Context, user *entity.User, plain string) {
+	if err := user.SetPassword(u.passwordHasher, plain); err != nil {
+		logger.FromContext(ctx).Named(logger.ComponentAuth).Errorf("failed to rehash password of user %s: %v", user.ID, err)
+		return
+	}
+	if _, err := u.userService.Update(ctx, user); err != nil {
+		logger.FromContext(ctx).Named(logger.ComponentAuth).Errorf("failed to store rehashed password of user %s: %v", user.ID, err)
+	}
+}
+
+// Logout is a method to revoke all stored auth tokens of the user, either all of them or, on any error, none.
+func (u *authUsecase) Logout(ctx context.Context, user *entity.User) error {
+	return u.transactor.Do(ctx, func(ctx context.Context) error {
+		if err := u.tokenService.DeleteToken(ctx, user.ID, auth.TokenTypeRefresh); err != nil {
+			return err
+		}
+		return u.tokenService.DeleteToken(ctx, user.ID, auth.TokenTypeAccess)
+	})
+}
+
+// userResponse is a function that converts a user and its tokens to the registration response.
+func userResponse(user *entity.User, tokens *dto.AuthTokens) *dto.UserRegisterResponse {
+	return &dto.UserRegisterResponse{
+		User: dto.UserReturn{
+			ID:            user.ID,
+			Email:         user.Email,
+			VerifiedEmail: user.VerifiedEmail,
+			Username:      user.Username,
+			Role:          user.Role,
+		},
+		Tokens: *tokens,
+	}
 }
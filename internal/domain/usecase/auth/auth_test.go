@@ -0,0 +1,110 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"webTemplate/internal/adapters/logger"
+	"webTemplate/internal/domain/common/errorz"
+	"webTemplate/internal/domain/dto"
+	"webTemplate/internal/domain/entity"
+	"webTemplate/internal/domain/utils/password"
+)
+
+// fakeUserService is a struct that keeps a single user and records its updates.
+type fakeUserService struct {
+	UserService
+	user      entity.User
+	updates   int
+	updateErr error
+}
+
+func (s *fakeUserService) GetByEmail(_ context.Context, email string) (*entity.User, error) {
+	if email != s.user.Email {
+		return nil, errorz.UserNotFound
+	}
+	user := s.user
+	return &user, nil
+}
+
+func (s *fakeUserService) Update(_ context.Context, user *entity.User) (*entity.User, error) {
+	s.updates++
+	if s.updateErr != nil {
+		return nil, s.updateErr
+	}
+	s.user = *user
+	return user, nil
+}
+
+// fakeTokenService is a struct that issues fixed auth tokens.
+type fakeTokenService struct {
+	TokenService
+}
+
+func (s *fakeTokenService) GenerateAuthTokens(_ context.Context, userID string) (*dto.AuthTokens, error) {
+	return &dto.AuthTokens{Access: dto.Token{Token: "access-" + userID}, Refresh: dto.Token{Token: "refresh-" + userID}}, nil
+}
+
+func TestLoginRehash(t *testing.T) {
+	if logger.Log == nil {
+		if err := logger.New(logger.Options{Outputs: []string{"stderr"}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	current := &password.Hasher{Algorithm: password.AlgorithmArgon2id, Argon2: password.Argon2Params{
+		Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32,
+	}}
+	outdated := &password.Hasher{Algorithm: password.AlgorithmBcrypt, BcryptCost: 4}
+
+	tests := []struct {
+		name        string
+		storedWith  *password.Hasher
+		password    string
+		updateErr   error
+		wantErr     error
+		wantUpdates int
+		wantRehash  bool
+	}{
+		{"current hash is kept", current, "secret", nil, nil, 0, false},
+		{"outdated hash is replaced", outdated, "secret", nil, nil, 1, true},
+		{"failed update doesn't fail the login", outdated, "secret", errors.New("database is down"), nil, 1, false},
+		{"wrong password isn't rehashed", outdated, "wrong", nil, errorz.InvalidCredentials, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hash, err := tt.storedWith.Hash("secret")
+			if err != nil {
+				t.Fatal(err)
+			}
+			users := &fakeUserService{
+				user:      entity.User{ID: "user-id", Email: "user@example.com", Password: []byte(hash)},
+				updateErr: tt.updateErr,
+			}
+			usecase := New(nil, users, &fakeTokenService{}, nil, nil, nil, current)
+
+			response, err := usecase.Login(context.Background(), dto.UserLogin{Email: "user@example.com", Password: tt.password})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Login() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && response.Tokens.Access.Token != "access-user-id" {
+				t.Errorf("Login() tokens = %+v", response.Tokens)
+			}
+			if users.updates != tt.wantUpdates {
+				t.Errorf("Update() calls = %d, want %d", users.updates, tt.wantUpdates)
+			}
+
+			rehashed := string(users.user.Password) != hash
+			if rehashed != tt.wantRehash {
+				t.Errorf("stored hash replaced = %t, want %t", rehashed, tt.wantRehash)
+			}
+			if !tt.wantRehash {
+				return
+			}
+			if needsRehash, errVerify := current.Verify("secret", string(users.user.Password)); errVerify != nil || needsRehash {
+				t.Errorf("Verify() of the new hash = (%t, %v), want (false, nil)", needsRehash, errVerify)
+			}
+		})
+	}
+}
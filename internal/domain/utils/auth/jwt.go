@@ -4,15 +4,19 @@ import (
 	"context"
 	"errors"
 	"github.com/golang-jwt/jwt/v5"
-	"github.com/spf13/viper"
 	"strings"
 	"time"
 	"webTemplate/internal/domain/common/errorz"
 	"webTemplate/internal/domain/entity"
 )
 
+// BearerToken is a function that returns the token of an Authorization header value.
+func BearerToken(authHeader string) string {
+	return strings.TrimSpace(strings.TrimPrefix(authHeader, "Bearer "))
+}
+
 func VerifyToken(authHeader, secret, tokenType string) (string, error) {
-	tokenStr := strings.TrimSpace(strings.TrimPrefix(authHeader, "Bearer "))
+	tokenStr := BearerToken(authHeader)
 	if tokenStr == "" {
 		return "", errorz.AuthHeaderIsEmpty
 	}
@@ -22,34 +26,37 @@ func VerifyToken(authHeader, secret, tokenType string) (string, error) {
 	})
 
 	if err != nil || !token.Valid {
-		return "", err
+		return "", errorz.InvalidToken.Wrap(err)
 	}
 
 	claims, ok := token.Claims.(jwt.MapClaims)
 	if !ok {
-		return "", errors.New("invalid token claims")
+		return "", errorz.InvalidToken.Wrap(errors.New("invalid token claims"))
 	}
 
 	jwtType, ok := claims["type"].(string)
 	if !ok || jwtType != tokenType {
-		return "", errors.New("invalid token type")
+		return "", errorz.InvalidToken.Wrap(errors.New("invalid token type"))
 	}
 
 	userID, ok := claims["sub"].(string)
 	if !ok {
-		return "", errors.New("invalid token sub")
+		return "", errorz.InvalidToken.Wrap(errors.New("invalid token sub"))
 	}
 
 	return userID, nil
 }
 
-func GetUserFromJWT(jwt, tokenType string, context context.Context, getUser func(context.Context, string) (*entity.User, error)) (*entity.User, error) {
-	id, errVerify := VerifyToken(jwt, viper.GetString("service.backend.jwt.secret"), tokenType)
+func GetUserFromJWT(jwt, secret, tokenType string, context context.Context, getUser func(context.Context, string) (*entity.User, error)) (*entity.User, error) {
+	id, errVerify := VerifyToken(jwt, secret, tokenType)
 	if errVerify != nil {
 		return &entity.User{}, errVerify
 	}
 
 	user, errGetUser := getUser(context, id)
+	if errors.Is(errGetUser, errorz.UserNotFound) {
+		return &entity.User{}, errorz.InvalidToken.Wrap(errGetUser)
+	}
 	if errGetUser != nil {
 		return &entity.User{}, errGetUser
 	}
@@ -57,7 +64,7 @@ func GetUserFromJWT(jwt, tokenType string, context context.Context, getUser func
 	return user, nil
 }
 
-func GenerateToken(userID string, expires time.Time, tokenType string) (string, error) {
+func GenerateToken(userID string, expires time.Time, tokenType, secret string) (string, error) {
 	claims := jwt.MapClaims{
 		"sub":  userID,
 		"iat":  time.Now().Unix(),
@@ -66,5 +73,5 @@ func GenerateToken(userID string, expires time.Time, tokenType string) (string,
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
-	return token.SignedString([]byte(viper.GetString("service.backend.jwt.secret")))
+	return token.SignedString([]byte(secret))
 }
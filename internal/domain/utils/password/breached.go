@@ -0,0 +1,83 @@
+package password
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	_ "embed"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// prefixLength is the length of the SHA-1 hex prefix used to bucket hashes, the same as in k-anonymity range APIs.
+const prefixLength = 5
+
+//go:embed breached.txt
+var bundledList []byte
+
+// BreachedList is a set of SHA-1 hashes of known-breached passwords, bucketed by hash prefix.
+type BreachedList struct {
+	buckets map[string]map[string]struct{}
+}
+
+// LoadBreachedList is a function that loads a breached password list from a file, empty path loads the bundled list.
+// The file contains one upper or lower case SHA-1 hex hash per line, optionally followed by ":<count>" as in HIBP dumps.
+func LoadBreachedList(path string) (*BreachedList, error) {
+	if path == "" {
+		return ParseBreachedList(bytes.NewReader(bundledList))
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func(file *os.File) {
+		_ = file.Close()
+	}(file)
+
+	return ParseBreachedList(file)
+}
+
+// ParseBreachedList is a function that reads a breached password list in LoadBreachedList format.
+func ParseBreachedList(r io.Reader) (*BreachedList, error) {
+	list := &BreachedList{buckets: make(map[string]map[string]struct{})}
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		hash := strings.TrimSpace(scanner.Text())
+		if i := strings.IndexByte(hash, ':'); i != -1 {
+			hash = hash[:i]
+		}
+		if hash == "" || strings.HasPrefix(hash, "#") {
+			continue
+		}
+		if len(hash) != sha1.Size*2 {
+			return nil, fmt.Errorf("breached password list line %d: invalid SHA-1 hash", line)
+		}
+
+		list.add(strings.ToUpper(hash))
+	}
+
+	return list, scanner.Err()
+}
+
+// Contains is a method that reports whether the password is in the list.
+func (l *BreachedList) Contains(password string) bool {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	_, found := l.buckets[hash[:prefixLength]][hash[prefixLength:]]
+	return found
+}
+
+// add is a method to put a hash into its prefix bucket.
+func (l *BreachedList) add(hash string) {
+	prefix, suffix := hash[:prefixLength], hash[prefixLength:]
+	if _, ok := l.buckets[prefix]; !ok {
+		l.buckets[prefix] = make(map[string]struct{})
+	}
+	l.buckets[prefix][suffix] = struct{}{}
+}
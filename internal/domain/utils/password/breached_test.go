@@ -0,0 +1,97 @@
+package password
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// sha1Hex is a function that returns the upper case SHA-1 hex hash of the password.
+func sha1Hex(password string) string {
+	sum := sha1.Sum([]byte(password))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+func TestParseBreachedList(t *testing.T) {
+	list, err := ParseBreachedList(strings.NewReader(strings.Join([]string{
+		"# breached passwords",
+		"",
+		sha1Hex("upper-hash"),
+		strings.ToLower(sha1Hex("lower-hash")),
+		"  " + sha1Hex("padded") + "\t",
+		sha1Hex("with-count") + ":42",
+		"   ",
+		"#" + sha1Hex("commented-out"),
+	}, "\n")))
+	if err != nil {
+		t.Fatalf("ParseBreachedList() error = %v", err)
+	}
+
+	tests := []struct {
+		password string
+		want     bool
+	}{
+		{"upper-hash", true},
+		{"lower-hash", true},
+		{"padded", true},
+		{"with-count", true},
+		{"commented-out", false},
+		{"UPPER-HASH", false},
+		{"not-listed", false},
+		{"", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.password, func(t *testing.T) {
+			if got := list.Contains(tt.password); got != tt.want {
+				t.Errorf("Contains(%q) = %t, want %t", tt.password, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseBreachedListInvalid(t *testing.T) {
+	for _, input := range []string{
+		sha1Hex("valid") + "\nnot-a-hash",
+		sha1Hex("truncated")[:39],
+		sha1Hex("too-long") + "0",
+	} {
+		if _, err := ParseBreachedList(strings.NewReader(input)); err == nil {
+			t.Errorf("ParseBreachedList(%q) error = nil, want an error", input)
+		}
+	}
+
+	_, err := ParseBreachedList(strings.NewReader("\n# comment\n" + "bad"))
+	if err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Errorf("ParseBreachedList() error = %v, want the line number", err)
+	}
+}
+
+func TestLoadBreachedList(t *testing.T) {
+	bundled, err := LoadBreachedList("")
+	if err != nil {
+		t.Fatalf("LoadBreachedList(\"\") error = %v", err)
+	}
+	if !bundled.Contains("password") {
+		t.Error("bundled list doesn't contain \"password\"")
+	}
+
+	path := filepath.Join(t.TempDir(), "breached.txt")
+	if err = os.WriteFile(path, []byte(sha1Hex("from-file")+":3\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	fromFile, err := LoadBreachedList(path)
+	if err != nil {
+		t.Fatalf("LoadBreachedList(%q) error = %v", path, err)
+	}
+	if !fromFile.Contains("from-file") || fromFile.Contains("password") {
+		t.Error("file list doesn't match the file contents")
+	}
+
+	if _, err = LoadBreachedList(filepath.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Error("LoadBreachedList() of a missing file error = nil, want an error")
+	}
+}
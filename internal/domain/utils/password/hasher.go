@@ -0,0 +1,151 @@
+package password
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"strings"
+)
+
+const (
+	AlgorithmBcrypt   = "bcrypt"
+	AlgorithmArgon2id = "argon2id"
+)
+
+var (
+	ErrMismatch       = errors.New("password does not match the hash")
+	ErrUnknownFormat  = errors.New("unknown password hash format")
+	ErrUnknownHashAlg = errors.New("unknown password hashing algorithm")
+)
+
+// Argon2Params is a struct that contains argon2id parameters.
+type Argon2Params struct {
+	Memory      uint32 // Memory in KiB
+	Iterations  uint32 // Number of passes over the memory
+	Parallelism uint8  // Number of threads
+	SaltLength  uint32 // Salt length in bytes
+	KeyLength   uint32 // Hash length in bytes
+}
+
+// Hasher is a struct that hashes passwords with the configured algorithm and verifies hashes of any supported algorithm.
+// Hashes record their algorithm and parameters: bcrypt hashes use the "$2a$<cost>$" format,
+// argon2id hashes use the PHC "$argon2id$v=19$m=<memory>,t=<iterations>,p=<parallelism>$<salt>$<hash>" format.
+type Hasher struct {
+	Algorithm  string       // AlgorithmBcrypt or AlgorithmArgon2id
+	BcryptCost int          // bcrypt cost, used by AlgorithmBcrypt
+	Argon2     Argon2Params // argon2id parameters, used by AlgorithmArgon2id
+}
+
+// Validate is a method that checks the hasher configuration.
+func (h *Hasher) Validate() error {
+	switch h.Algorithm {
+	case AlgorithmBcrypt:
+		if h.BcryptCost < bcrypt.MinCost || h.BcryptCost > bcrypt.MaxCost {
+			return fmt.Errorf("bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+		}
+	case AlgorithmArgon2id:
+		if h.Argon2.Memory == 0 || h.Argon2.Iterations == 0 || h.Argon2.Parallelism == 0 {
+			return errors.New("argon2id memory, iterations and parallelism must be positive")
+		}
+		if h.Argon2.SaltLength < 8 || h.Argon2.KeyLength < 16 {
+			return errors.New("argon2id salt must be at least 8 bytes and key at least 16 bytes long")
+		}
+	default:
+		return ErrUnknownHashAlg
+	}
+	return nil
+}
+
+// Hash is a method that hashes the password with the configured algorithm.
+func (h *Hasher) Hash(password string) (string, error) {
+	switch h.Algorithm {
+	case AlgorithmBcrypt:
+		hash, err := bcrypt.GenerateFromPassword([]byte(password), h.BcryptCost)
+		return string(hash), err
+	case AlgorithmArgon2id:
+		salt := make([]byte, h.Argon2.SaltLength)
+		if _, err := rand.Read(salt); err != nil {
+			return "", err
+		}
+		key := argon2.IDKey([]byte(password), salt, h.Argon2.Iterations, h.Argon2.Memory, h.Argon2.Parallelism, h.Argon2.KeyLength)
+		return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+			argon2.Version, h.Argon2.Memory, h.Argon2.Iterations, h.Argon2.Parallelism,
+			base64.RawStdEncoding.EncodeToString(salt),
+			base64.RawStdEncoding.EncodeToString(key),
+		), nil
+	default:
+		return "", ErrUnknownHashAlg
+	}
+}
+
+// Verify is a method that compares the password with a hash of any supported algorithm
+/*
+ * returns needsRehash - true when the password matches, but the hash uses another algorithm or outdated parameters
+ * returns err - ErrMismatch if the password doesn't match
+ */
+func (h *Hasher) Verify(password, encoded string) (needsRehash bool, err error) {
+	switch {
+	case strings.HasPrefix(encoded, "$2"):
+		if err = bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password)); err != nil {
+			if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+				return false, ErrMismatch
+			}
+			return false, err
+		}
+		cost, costErr := bcrypt.Cost([]byte(encoded))
+		if costErr != nil {
+			return false, costErr
+		}
+		return h.Algorithm != AlgorithmBcrypt || cost != h.BcryptCost, nil
+	case strings.HasPrefix(encoded, "$"+AlgorithmArgon2id+"$"):
+		params, salt, key, err := decodeArgon2id(encoded)
+		if err != nil {
+			return false, err
+		}
+		actual := argon2.IDKey([]byte(password), salt, params.Iterations, params.Memory, params.Parallelism, params.KeyLength)
+		if subtle.ConstantTimeCompare(actual, key) != 1 {
+			return false, ErrMismatch
+		}
+		return h.Algorithm != AlgorithmArgon2id || params != h.Argon2, nil
+	default:
+		return false, ErrUnknownFormat
+	}
+}
+
+// decodeArgon2id is a function that parses an argon2id hash in PHC format.
+func decodeArgon2id(encoded string) (Argon2Params, []byte, []byte, error) {
+	var params Argon2Params
+
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 {
+		return params, nil, nil, ErrUnknownFormat
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return params, nil, nil, ErrUnknownFormat
+	}
+	// argon2.IDKey panics on zero iterations or parallelism
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &params.Memory, &params.Iterations, &params.Parallelism); err != nil ||
+		params.Memory == 0 || params.Iterations == 0 || params.Parallelism == 0 {
+		return params, nil, nil, ErrUnknownFormat
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil || len(salt) == 0 {
+		return params, nil, nil, ErrUnknownFormat
+	}
+	// An empty key would match any password
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return params, nil, nil, ErrUnknownFormat
+	}
+	params.SaltLength = uint32(len(salt))
+	params.KeyLength = uint32(len(key))
+
+	return params, salt, key, nil
+}
@@ -0,0 +1,132 @@
+package password
+
+import (
+	"errors"
+	"golang.org/x/crypto/bcrypt"
+	"strings"
+	"testing"
+)
+
+// testArgon2 is a set of cheap argon2id parameters, so the tests stay fast.
+var testArgon2 = Argon2Params{Memory: 64, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+// mustHash is a function that hashes the password or fails the test.
+func mustHash(t *testing.T, hasher *Hasher, password string) string {
+	t.Helper()
+
+	hash, err := hasher.Hash(password)
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+	return hash
+}
+
+func TestHasherVerifyNeedsRehash(t *testing.T) {
+	bcryptHasher := &Hasher{Algorithm: AlgorithmBcrypt, BcryptCost: bcrypt.MinCost}
+	argon2Hasher := &Hasher{Algorithm: AlgorithmArgon2id, Argon2: testArgon2}
+
+	withArgon2 := func(change func(params *Argon2Params)) *Hasher {
+		params := testArgon2
+		change(&params)
+		return &Hasher{Algorithm: AlgorithmArgon2id, Argon2: params}
+	}
+
+	bcryptHash := mustHash(t, bcryptHasher, "secret")
+	argon2Hash := mustHash(t, argon2Hasher, "secret")
+
+	tests := []struct {
+		name   string
+		hasher *Hasher
+		hash   string
+		want   bool
+	}{
+		{"bcrypt same cost", bcryptHasher, bcryptHash, false},
+		{"bcrypt cost raised", &Hasher{Algorithm: AlgorithmBcrypt, BcryptCost: bcrypt.MinCost + 1}, bcryptHash, true},
+		{"bcrypt hash of a higher cost", &Hasher{Algorithm: AlgorithmBcrypt, BcryptCost: bcrypt.MinCost}, mustHash(t, &Hasher{Algorithm: AlgorithmBcrypt, BcryptCost: bcrypt.MinCost + 1}, "secret"), true},
+		{"bcrypt to argon2id", argon2Hasher, bcryptHash, true},
+		{"argon2id same params", argon2Hasher, argon2Hash, false},
+		{"argon2id memory changed", withArgon2(func(p *Argon2Params) { p.Memory *= 2 }), argon2Hash, true},
+		{"argon2id iterations changed", withArgon2(func(p *Argon2Params) { p.Iterations++ }), argon2Hash, true},
+		{"argon2id parallelism changed", withArgon2(func(p *Argon2Params) { p.Parallelism++ }), argon2Hash, true},
+		{"argon2id salt length changed", withArgon2(func(p *Argon2Params) { p.SaltLength = 32 }), argon2Hash, true},
+		{"argon2id key length changed", withArgon2(func(p *Argon2Params) { p.KeyLength = 64 }), argon2Hash, true},
+		{"argon2id to bcrypt", bcryptHasher, argon2Hash, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			needsRehash, err := tt.hasher.Verify("secret", tt.hash)
+			if err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+			if needsRehash != tt.want {
+				t.Errorf("Verify() needsRehash = %t, want %t", needsRehash, tt.want)
+			}
+
+			needsRehash, err = tt.hasher.Verify("other", tt.hash)
+			if !errors.Is(err, ErrMismatch) || needsRehash {
+				t.Errorf("Verify() of a wrong password = (%t, %v), want (false, ErrMismatch)", needsRehash, err)
+			}
+		})
+	}
+}
+
+func TestHasherVerifyUnknownFormat(t *testing.T) {
+	hasher := &Hasher{Algorithm: AlgorithmArgon2id, Argon2: testArgon2}
+	for _, hash := range []string{"", "secret", "$1$md5crypt$hash", "$argon2i$v=19$m=64,t=1,p=1$c2FsdHNhbHQ$a2V5a2V5"} {
+		if _, err := hasher.Verify("secret", hash); !errors.Is(err, ErrUnknownFormat) {
+			t.Errorf("Verify(%q) error = %v, want ErrUnknownFormat", hash, err)
+		}
+	}
+}
+
+func TestDecodeArgon2id(t *testing.T) {
+	valid := mustHash(t, &Hasher{Algorithm: AlgorithmArgon2id, Argon2: testArgon2}, "secret")
+	parts := strings.Split(valid, "$")
+	replace := func(i int, value string) string {
+		changed := append([]string(nil), parts...)
+		changed[i] = value
+		return strings.Join(changed, "$")
+	}
+
+	params, salt, key, err := decodeArgon2id(valid)
+	if err != nil {
+		t.Fatalf("decodeArgon2id(%q) error = %v", valid, err)
+	}
+	if params != testArgon2 || len(salt) != int(testArgon2.SaltLength) || len(key) != int(testArgon2.KeyLength) {
+		t.Errorf("decodeArgon2id() = %+v, %d byte salt, %d byte key", params, len(salt), len(key))
+	}
+
+	tests := []struct {
+		name string
+		hash string
+	}{
+		{"too few parts", strings.Join(parts[:5], "$")},
+		{"too many parts", valid + "$extra"},
+		{"other version", replace(2, "v=16")},
+		{"no version", replace(2, "19")},
+		{"missing params", replace(3, "m=64,t=1")},
+		{"non-numeric params", replace(3, "m=x,t=1,p=1")},
+		{"negative memory", replace(3, "m=-1,t=1,p=1")},
+		{"zero memory", replace(3, "m=0,t=1,p=1")},
+		{"zero iterations", replace(3, "m=64,t=0,p=1")},
+		{"zero parallelism", replace(3, "m=64,t=1,p=0")},
+		{"parallelism overflow", replace(3, "m=64,t=1,p=256")},
+		{"salt not base64", replace(4, "not base64!")},
+		{"padded salt", replace(4, parts[4]+"==")},
+		{"empty salt", replace(4, "")},
+		{"key not base64", replace(5, "not base64!")},
+		{"empty key", replace(5, "")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, _, err := decodeArgon2id(tt.hash); !errors.Is(err, ErrUnknownFormat) {
+				t.Errorf("decodeArgon2id(%q) error = %v, want ErrUnknownFormat", tt.hash, err)
+			}
+			if _, err := (&Hasher{Algorithm: AlgorithmArgon2id, Argon2: testArgon2}).Verify("secret", tt.hash); err == nil {
+				t.Errorf("Verify(%q) error = nil, want an error", tt.hash)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package password
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+	"webTemplate/internal/domain/common/errorz"
+)
+
+// minPersonalLength is the minimal length of an email local part or username checked by DisallowPersonalInfo.
+const minPersonalLength = 3
+
+// Policy is a struct that contains the requirements passwords must meet on registration, change and reset.
+type Policy struct {
+	MinLength            int           // Minimal length in characters
+	MaxLength            int           // Maximal length in bytes, 0 for no limit
+	RequireUpper         bool          // Require an upper case letter
+	RequireLower         bool          // Require a lower case letter
+	RequireDigit         bool          // Require a digit
+	RequireSymbol        bool          // Require a character that is not a letter or a digit
+	DisallowPersonalInfo bool          // Forbid the email local part and the username inside the password
+	MinStrength          float64       // Minimal estimated entropy in bits, see Strength
+	Breached             *BreachedList // Known-breached passwords, nil disables the check
+}
+
+// Check is a method that returns all requirements the password fails, an empty result means the password is accepted
+/*
+ * password string - password to check
+ * personal ...string - email and username of the user, checked when DisallowPersonalInfo is set
+ */
+func (p *Policy) Check(password string, personal ...string) []errorz.FieldError {
+	var violations []errorz.FieldError
+	violate := func(rule, message string, params map[string]string) {
+		violations = append(violations, errorz.FieldError{
+			Field:   "password",
+			Rule:    rule,
+			Message: message,
+			Params:  params,
+		})
+	}
+
+	if length := utf8.RuneCountInString(password); length < p.MinLength || (p.MaxLength > 0 && len(password) > p.MaxLength) {
+		message := fmt.Sprintf("password must be %d to %d characters long", p.MinLength, p.MaxLength)
+		if p.MaxLength == 0 {
+			message = fmt.Sprintf("password must be at least %d characters long", p.MinLength)
+		}
+		violate("password_length", message, map[string]string{
+			"min": strconv.Itoa(p.MinLength),
+			"max": strconv.Itoa(p.MaxLength),
+		})
+	}
+
+	classes := []struct {
+		required bool
+		rule     string
+		name     string
+		matches  func(rune) bool
+	}{
+		{p.RequireUpper, "password_upper", "an upper case letter", unicode.IsUpper},
+		{p.RequireLower, "password_lower", "a lower case letter", unicode.IsLower},
+		{p.RequireDigit, "password_digit", "a digit", unicode.IsDigit},
+		{p.RequireSymbol, "password_symbol", "a symbol", func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.IsSpace(r)
+		}},
+	}
+	for _, class := range classes {
+		if class.required && strings.IndexFunc(password, class.matches) == -1 {
+			violate(class.rule, "password must contain "+class.name, nil)
+		}
+	}
+
+	if p.DisallowPersonalInfo && containsPersonalInfo(password, personal) {
+		violate("password_personal", "password must not contain your email or username", nil)
+	}
+
+	if p.MinStrength > 0 && Strength(password) < p.MinStrength {
+		violate("password_weak",
+			"password is too easy to guess",
+			map[string]string{"min": strconv.FormatFloat(p.MinStrength, 'f', -1, 64)},
+		)
+	}
+
+	if p.Breached != nil && p.Breached.Contains(password) {
+		violate("password_breached", "password has appeared in a data breach, choose another one", nil)
+	}
+
+	return violations
+}
+
+// Validate is a method that checks the password and returns an errorz.CodeValidation error, if it fails any requirement.
+func (p *Policy) Validate(password string, personal ...string) error {
+	if violations := p.Check(password, personal...); len(violations) > 0 {
+		return errorz.Validation("password does not meet the password policy", violations)
+	}
+	return nil
+}
+
+// containsPersonalInfo is a function that reports whether the password contains an email local part or a username.
+func containsPersonalInfo(password string, personal []string) bool {
+	password = strings.ToLower(password)
+
+	for _, value := range personal {
+		value = strings.ToLower(value)
+		if i := strings.IndexByte(value, '@'); i != -1 {
+			value = value[:i]
+		}
+		if utf8.RuneCountInString(value) >= minPersonalLength && strings.Contains(password, value) {
+			return true
+		}
+	}
+	return false
+}
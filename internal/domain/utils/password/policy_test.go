@@ -0,0 +1,113 @@
+package password
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+// rules is a function that returns the rules of the violations in order.
+func rules(t *testing.T, policy *Policy, password string, personal ...string) []string {
+	t.Helper()
+
+	var result []string
+	for _, violation := range policy.Check(password, personal...) {
+		if violation.Field != "password" {
+			t.Errorf("violation field = %q, want \"password\"", violation.Field)
+		}
+		result = append(result, violation.Rule)
+	}
+	return result
+}
+
+func TestPolicyCheck(t *testing.T) {
+	breached, err := ParseBreachedList(strings.NewReader(sha1Hex("Summer2024!") + "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	length := &Policy{MinLength: 8, MaxLength: 16}
+	classes := &Policy{MinLength: 1, RequireUpper: true, RequireLower: true, RequireDigit: true, RequireSymbol: true}
+	personal := &Policy{MinLength: 1, DisallowPersonalInfo: true}
+	strength := &Policy{MinLength: 1, MinStrength: Strength("kx7Qm2pz")}
+	withBreached := &Policy{MinLength: 1, Breached: breached}
+
+	tests := []struct {
+		name      string
+		policy    *Policy
+		password  string
+		personal  []string
+		wantRules []string
+	}{
+		{"shorter than min", length, "abcdefg", nil, []string{"password_length"}},
+		{"exactly min", length, "abcdefgh", nil, nil},
+		{"exactly max", length, strings.Repeat("a", 16), nil, nil},
+		{"longer than max", length, strings.Repeat("a", 17), nil, []string{"password_length"}},
+		{"min counts characters", length, "пароль12", nil, nil},
+		{"max counts bytes", length, "парольпароль", nil, []string{"password_length"}},
+		{"no max", &Policy{MinLength: 8}, strings.Repeat("a", 1000), nil, nil},
+		{"empty", length, "", nil, []string{"password_length"}},
+
+		{"all classes", classes, "Aa1!", nil, nil},
+		{"no upper", classes, "aa1!", nil, []string{"password_upper"}},
+		{"no lower", classes, "AA1!", nil, []string{"password_lower"}},
+		{"no digit", classes, "Aa!!", nil, []string{"password_digit"}},
+		{"no symbol", classes, "Aa11", nil, []string{"password_symbol"}},
+		{"space is not a symbol", classes, "Aa1 ", nil, []string{"password_symbol"}},
+		{"non-latin letters count", classes, "Яя1!", nil, nil},
+		{"every class missing", classes, "    ", nil, []string{"password_upper", "password_lower", "password_digit", "password_symbol"}},
+
+		{"contains the username", personal, "my-Alice-pass", []string{"alice@example.com", "alice"}, []string{"password_personal"}},
+		{"contains the email local part", personal, "xBOBBYx", []string{"bobby@example.com"}, []string{"password_personal"}},
+		{"contains the email domain only", personal, "example.com!", []string{"bobby@example.com"}, nil},
+		{"short username is ignored", personal, "jo12345", []string{"jo"}, nil},
+		{"no personal info given", personal, "alice", nil, nil},
+		{"personal info check disabled", &Policy{MinLength: 1}, "alice", []string{"alice"}, nil},
+
+		{"at the strength threshold", strength, "kx7Qm2pz", nil, nil},
+		{"below the strength threshold", strength, "kx7Qm2p", nil, []string{"password_weak"}},
+		{"runs are weaker", strength, "abcdefgh", nil, []string{"password_weak"}},
+
+		{"breached", withBreached, "Summer2024!", nil, []string{"password_breached"}},
+		{"breached is case sensitive", withBreached, "summer2024!", nil, nil},
+		{"several violations", &Policy{MinLength: 8, RequireDigit: true, Breached: breached}, "abc", nil, []string{"password_length", "password_digit"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rules(t, tt.policy, tt.password, tt.personal...); !slices.Equal(got, tt.wantRules) {
+				t.Errorf("Check(%q) rules = %v, want %v", tt.password, got, tt.wantRules)
+			}
+		})
+	}
+}
+
+func TestPolicyCheckParams(t *testing.T) {
+	violations := (&Policy{MinLength: 8, MaxLength: 64, MinStrength: 40}).Check("aaa")
+	if len(violations) != 2 {
+		t.Fatalf("Check() = %+v, want length and strength violations", violations)
+	}
+
+	length := violations[0]
+	if length.Params["min"] != "8" || length.Params["max"] != "64" || length.Message != "password must be 8 to 64 characters long" {
+		t.Errorf("length violation = %+v", length)
+	}
+	if weak := violations[1]; weak.Params["min"] != "40" {
+		t.Errorf("strength violation params = %v, want min 40", weak.Params)
+	}
+
+	noMax := (&Policy{MinLength: 8}).Check("aaa")
+	if len(noMax) != 1 || noMax[0].Message != "password must be at least 8 characters long" {
+		t.Errorf("Check() without max = %+v", noMax)
+	}
+}
+
+func TestPolicyValidate(t *testing.T) {
+	policy := &Policy{MinLength: 8}
+	if err := policy.Validate("long enough"); err != nil {
+		t.Errorf("Validate() error = %v, want nil", err)
+	}
+	if err := policy.Validate("short"); err == nil {
+		t.Error("Validate() error = nil, want a validation error")
+	}
+}
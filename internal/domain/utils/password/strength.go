@@ -0,0 +1,57 @@
+package password
+
+import (
+	"math"
+	"unicode"
+)
+
+// Strength is a function that estimates the entropy of a password in bits.
+// The estimate is the size of the used character pool raised to the effective length,
+// where repeated characters and ascending or descending runs like "aaa" or "123" count as half a character.
+func Strength(password string) float64 {
+	var hasLower, hasUpper, hasDigit, hasSymbol, hasOther bool
+	runes := []rune(password)
+
+	effectiveLength := 0.0
+	for i, r := range runes {
+		switch {
+		case r < unicode.MaxASCII && unicode.IsLower(r):
+			hasLower = true
+		case r < unicode.MaxASCII && unicode.IsUpper(r):
+			hasUpper = true
+		case r < unicode.MaxASCII && unicode.IsDigit(r):
+			hasDigit = true
+		case r < unicode.MaxASCII && unicode.IsPrint(r):
+			hasSymbol = true
+		default:
+			hasOther = true
+		}
+
+		if i > 0 && (r == runes[i-1] || r == runes[i-1]+1 || r == runes[i-1]-1) {
+			effectiveLength += 0.5
+		} else {
+			effectiveLength++
+		}
+	}
+
+	pool := 0
+	for _, class := range []struct {
+		used bool
+		size int
+	}{
+		{hasLower, 26},
+		{hasUpper, 26},
+		{hasDigit, 10},
+		{hasSymbol, 33},
+		{hasOther, 100},
+	} {
+		if class.used {
+			pool += class.size
+		}
+	}
+	if pool == 0 {
+		return 0
+	}
+
+	return effectiveLength * math.Log2(float64(pool))
+}
@@ -0,0 +1,43 @@
+package password
+
+import (
+	"math"
+	"testing"
+)
+
+func TestStrength(t *testing.T) {
+	bits := func(length float64, pool int) float64 {
+		return length * math.Log2(float64(pool))
+	}
+
+	tests := []struct {
+		name     string
+		password string
+		want     float64
+	}{
+		{"empty", "", 0},
+		{"single lower", "q", bits(1, 26)},
+		{"single upper", "Q", bits(1, 26)},
+		{"single digit", "7", bits(1, 10)},
+		{"single symbol", "!", bits(1, 33)},
+		{"space is a symbol", " ", bits(1, 33)},
+		{"non-ascii", "ж", bits(1, 100)},
+		{"lower and upper", "qZ", bits(2, 52)},
+		{"all ascii classes", "qZ7!", bits(4, 95)},
+		{"every class", "qZ7!ж", bits(5, 195)},
+		{"repeats count half", "qqqq", bits(2.5, 26)},
+		{"ascending run counts half", "abcd", bits(2.5, 26)},
+		{"descending run counts half", "4321", bits(2.5, 10)},
+		{"run across classes", "9:", bits(1.5, 43)},
+		{"no runs", "qzqz", bits(4, 26)},
+		{"run restarts", "abxy", bits(3, 26)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Strength(tt.password); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("Strength(%q) = %f, want %f", tt.password, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package timezone
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// maxOffset and minOffset are the limits of real-world UTC offsets
+	maxOffset = 14 * time.Hour
+	minOffset = -12 * time.Hour
+)
+
+// offsetPattern matches offsets like "GMT+3", "UTC-05:30", "GMT+0530" or "+03:00".
+var offsetPattern = regexp.MustCompile(`^(?i:GMT|UTC)?([+-])(\d{1,2})(?::?(\d{2}))?$`)
+
+var ErrUnknownZone = errors.New("unknown time zone")
+
+// Zone is a struct that contains a parsed time zone setting and its names for other systems.
+type Zone struct {
+	location *time.Location
+	offset   time.Duration // UTC offset of fixed zones
+	fixed    bool
+}
+
+// Parse is a function that parses an IANA zone name like "Europe/Moscow" or a fixed UTC offset like "GMT+3".
+// An empty name, "UTC" and "GMT" are UTC.
+func Parse(name string) (*Zone, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || strings.EqualFold(name, "UTC") || strings.EqualFold(name, "GMT") || name == "Z" {
+		return &Zone{location: time.UTC, fixed: true}, nil
+	}
+
+	if match := offsetPattern.FindStringSubmatch(name); match != nil {
+		hours, _ := strconv.Atoi(match[2])
+		minutes := 0
+		if match[3] != "" {
+			minutes, _ = strconv.Atoi(match[3])
+		}
+		if minutes >= 60 {
+			return nil, fmt.Errorf("%w: %q has invalid minutes", ErrUnknownZone, name)
+		}
+
+		offset := time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute
+		if match[1] == "-" {
+			offset = -offset
+		}
+		if offset > maxOffset || offset < minOffset {
+			return nil, fmt.Errorf("%w: %q is out of the %s to %s range", ErrUnknownZone, name, offsetName(minOffset), offsetName(maxOffset))
+		}
+
+		return &Zone{
+			location: time.FixedZone(offsetName(offset), int(offset.Seconds())),
+			offset:   offset,
+			fixed:    true,
+		}, nil
+	}
+
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownZone, name)
+	}
+	return &Zone{location: location}, nil
+}
+
+// MustParse is a function like Parse that panics on error, it is meant for constants and tests.
+func MustParse(name string) *Zone {
+	zone, err := Parse(name)
+	if err != nil {
+		panic(err)
+	}
+	return zone
+}
+
+// Location is a method that returns the zone as a *time.Location.
+func (z *Zone) Location() *time.Location {
+	return z.location
+}
+
+// String is a method that returns the display name of the zone, e.g. "Europe/Moscow", "GMT+3" or "UTC".
+func (z *Zone) String() string {
+	return z.location.String()
+}
+
+// IANA is a method that returns a name accepted by time.LoadLocation and the IANA database, "" if there is none.
+// Whole-hour offsets use the "Etc/GMT" zones, whose sign is inverted: GMT+3 is "Etc/GMT-3".
+func (z *Zone) IANA() string {
+	if !z.fixed {
+		return z.location.String()
+	}
+	if z.offset == 0 {
+		return "UTC"
+	}
+	if z.offset%time.Hour != 0 {
+		return ""
+	}
+
+	hours := int(z.offset / time.Hour)
+	if hours > 0 {
+		return "Etc/GMT-" + strconv.Itoa(hours)
+	}
+	return "Etc/GMT+" + strconv.Itoa(-hours)
+}
+
+// Postgres is a method that returns the value of the postgres TimeZone setting for the zone.
+// Postgres reads offsets like "GMT+3" in the inverted POSIX sense, so they are converted to IANA or POSIX names.
+func (z *Zone) Postgres() string {
+	if name := z.IANA(); name != "" {
+		return name
+	}
+
+	// POSIX offsets are west of Greenwich, e.g. "<+0530>-05:30" is UTC+05:30
+	hours := int(z.offset / time.Hour)
+	minutes := int((z.offset % time.Hour) / time.Minute)
+	if minutes < 0 {
+		minutes = -minutes
+	}
+	return fmt.Sprintf("<%s>%s%02d:%02d", compactOffset(z.offset), invertedSign(z.offset), abs(hours), minutes)
+}
+
+// offsetName is a function that returns the display name of a fixed offset, e.g. "GMT+3" or "GMT-05:30".
+func offsetName(offset time.Duration) string {
+	sign := "+"
+	if offset < 0 {
+		sign = "-"
+		offset = -offset
+	}
+
+	hours := int(offset / time.Hour)
+	minutes := int((offset % time.Hour) / time.Minute)
+	if minutes == 0 {
+		return fmt.Sprintf("GMT%s%d", sign, hours)
+	}
+	return fmt.Sprintf("GMT%s%02d:%02d", sign, hours, minutes)
+}
+
+// compactOffset is a function that returns an offset as "+hhmm", the form used in POSIX zone abbreviations.
+func compactOffset(offset time.Duration) string {
+	sign := "+"
+	if offset < 0 {
+		sign = "-"
+		offset = -offset
+	}
+	return fmt.Sprintf("%s%02d%02d", sign, int(offset/time.Hour), int((offset%time.Hour)/time.Minute))
+}
+
+// invertedSign is a function that returns the POSIX sign of an offset, "-" for zones east of Greenwich.
+func invertedSign(offset time.Duration) string {
+	if offset > 0 {
+		return "-"
+	}
+	return "+"
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,80 @@
+package timezone
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	// 2024-01-15 12:00 UTC, outside of daylight saving time in the northern hemisphere
+	instant := time.Date(2024, time.January, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		input      string
+		wantName   string
+		wantOffset time.Duration
+		wantIANA   string
+		wantPG     string
+	}{
+		{"empty is UTC", "", "UTC", 0, "UTC", "UTC"},
+		{"UTC", "UTC", "UTC", 0, "UTC", "UTC"},
+		{"GMT", "gmt", "UTC", 0, "UTC", "UTC"},
+		{"GMT plus hours", "GMT+3", "GMT+3", 3 * time.Hour, "Etc/GMT-3", "Etc/GMT-3"},
+		{"GMT minus hours", "GMT-5", "GMT-5", -5 * time.Hour, "Etc/GMT+5", "Etc/GMT+5"},
+		{"UTC with minutes", "UTC+05:30", "GMT+05:30", 5*time.Hour + 30*time.Minute, "", "<+0530>-05:30"},
+		{"negative minutes", "GMT-0330", "GMT-03:30", -3*time.Hour - 30*time.Minute, "", "<-0330>+03:30"},
+		{"bare offset", "+14", "GMT+14", 14 * time.Hour, "Etc/GMT-14", "Etc/GMT-14"},
+		{"IANA", "Europe/Moscow", "Europe/Moscow", 3 * time.Hour, "Europe/Moscow", "Europe/Moscow"},
+		{"IANA with DST", "America/New_York", "America/New_York", -5 * time.Hour, "America/New_York", "America/New_York"},
+		{"IANA Etc keeps its inverted sign", "Etc/GMT-3", "Etc/GMT-3", 3 * time.Hour, "Etc/GMT-3", "Etc/GMT-3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			zone, err := Parse(tt.input)
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.input, err)
+			}
+
+			if got := zone.String(); got != tt.wantName {
+				t.Errorf("String() = %q, want %q", got, tt.wantName)
+			}
+			if _, offset := instant.In(zone.Location()).Zone(); time.Duration(offset)*time.Second != tt.wantOffset {
+				t.Errorf("offset = %s, want %s", time.Duration(offset)*time.Second, tt.wantOffset)
+			}
+			if got := zone.IANA(); got != tt.wantIANA {
+				t.Errorf("IANA() = %q, want %q", got, tt.wantIANA)
+			}
+			if got := zone.Postgres(); got != tt.wantPG {
+				t.Errorf("Postgres() = %q, want %q", got, tt.wantPG)
+			}
+		})
+	}
+}
+
+func TestParseIANAMatchesOffset(t *testing.T) {
+	for _, input := range []string{"GMT+3", "GMT-5", "GMT+12", "GMT-12"} {
+		zone := MustParse(input)
+		location, err := time.LoadLocation(zone.IANA())
+		if err != nil {
+			t.Fatalf("LoadLocation(%q) error = %v", zone.IANA(), err)
+		}
+
+		now := time.Now()
+		_, want := now.In(zone.Location()).Zone()
+		_, got := now.In(location).Zone()
+		if got != want {
+			t.Errorf("%s: IANA zone %q offset = %d, want %d", input, zone.IANA(), got, want)
+		}
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, input := range []string{"GMT+15", "GMT-13", "UTC+03:60", "Mars/Olympus_Mons", "GMT+", "three"} {
+		if _, err := Parse(input); !errors.Is(err, ErrUnknownZone) {
+			t.Errorf("Parse(%q) error = %v, want ErrUnknownZone", input, err)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package username
+
+import (
+	"golang.org/x/text/unicode/norm"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+	"webTemplate/internal/domain/common/errorz"
+)
+
+const (
+	ClassLetters = "letters"
+	ClassDigits  = "digits"
+)
+
+// Normalize is a function that returns the display form of a username: trimmed and NFKC-normalized, case is kept.
+func Normalize(username string) string {
+	return norm.NFKC.String(strings.TrimSpace(username))
+}
+
+// Canonical is a function that returns the form usernames are compared and stored uniquely in: NFKC-normalized and lower case.
+func Canonical(username string) string {
+	return strings.ToLower(Normalize(username))
+}
+
+// CanonicalEmail is a function that returns the form emails are compared and stored uniquely in.
+func CanonicalEmail(email string) string {
+	return strings.ToLower(norm.NFKC.String(strings.TrimSpace(email)))
+}
+
+// Rules is a struct that contains the requirements usernames must meet.
+type Rules struct {
+	MinLength      int      // Minimal length in characters
+	MaxLength      int      // Maximal length in characters
+	AllowedClasses []string // Allowed character classes: ClassLetters and ClassDigits, in any script
+	AllowedSymbols string   // Allowed characters besides the classes, e.g. "_.-"
+	Reserved       []string // Names that can't be registered, compared in canonical form
+}
+
+// Check is a method that returns all requirements the username fails, an empty result means the username is accepted.
+func (r *Rules) Check(username string) []errorz.FieldError {
+	var violations []errorz.FieldError
+	violate := func(rule, message string, params map[string]string) {
+		violations = append(violations, errorz.FieldError{
+			Field:   "username",
+			Rule:    rule,
+			Message: message,
+			Params:  params,
+		})
+	}
+
+	normalized := Normalize(username)
+	if length := utf8.RuneCountInString(normalized); length < r.MinLength || length > r.MaxLength {
+		violate("username_length",
+			"username must be "+strconv.Itoa(r.MinLength)+" to "+strconv.Itoa(r.MaxLength)+" characters long",
+			map[string]string{"min": strconv.Itoa(r.MinLength), "max": strconv.Itoa(r.MaxLength)},
+		)
+	}
+
+	if strings.IndexFunc(normalized, func(c rune) bool { return !r.allowed(c) }) != -1 {
+		violate("username_chars",
+			"username may only contain "+r.describeAllowed(),
+			map[string]string{"classes": strings.Join(r.AllowedClasses, ", "), "symbols": r.AllowedSymbols},
+		)
+	}
+
+	if r.IsReserved(normalized) {
+		violate("username_reserved", "username is reserved", nil)
+	}
+
+	return violations
+}
+
+// IsReserved is a method that reports whether the username is in the reserved list.
+func (r *Rules) IsReserved(username string) bool {
+	canonical := Canonical(username)
+	for _, reserved := range r.Reserved {
+		if Canonical(reserved) == canonical {
+			return true
+		}
+	}
+	return false
+}
+
+// allowed is a method that reports whether a character may be used in usernames.
+func (r *Rules) allowed(c rune) bool {
+	for _, class := range r.AllowedClasses {
+		switch class {
+		case ClassLetters:
+			if unicode.IsLetter(c) || unicode.IsMark(c) {
+				return true
+			}
+		case ClassDigits:
+			if unicode.IsDigit(c) {
+				return true
+			}
+		}
+	}
+	return strings.ContainsRune(r.AllowedSymbols, c)
+}
+
+// describeAllowed is a method that returns a human-readable list of allowed characters.
+func (r *Rules) describeAllowed() string {
+	parts := append([]string{}, r.AllowedClasses...)
+	if r.AllowedSymbols != "" {
+		parts = append(parts, "\""+r.AllowedSymbols+"\"")
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,123 @@
+package username
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCanonical(t *testing.T) {
+	tests := []struct {
+		name          string
+		input         string
+		wantNormal    string
+		wantCanonical string
+	}{
+		{"ascii", "Alice", "Alice", "alice"},
+		{"trimmed", "  Alice\t", "Alice", "alice"},
+		{"full-width", "ＡＬＩＣＥ１２", "ALICE12", "alice12"},
+		{"full-width symbols", "bob＿ｓｍｉｔｈ", "bob_smith", "bob_smith"},
+		{"ligature", "ﬁnn", "finn", "finn"},
+		{"superscript digit", "x²", "x2", "x2"},
+		{"circled digit", "agent①", "agent1", "agent1"},
+		{"roman numeral", "Ⅻ", "XII", "xii"},
+		{"decomposed accent", "Jose\u0301", "Jos\u00e9", "jos\u00e9"},
+		{"precomposed accent", "Jos\u00e9", "Jos\u00e9", "jos\u00e9"},
+		{"cyrillic", "Иван", "Иван", "иван"},
+		{"cyrillic full upper case", "ПЁТР", "ПЁТР", "пётр"},
+		{"greek final sigma is not special", "ΟΔΥΣΣΕΑΣ", "ΟΔΥΣΣΕΑΣ", "οδυσσεασ"},
+		// Confusables are not folded: a Cyrillic "а" stays a different letter than the Latin "a"
+		{"cyrillic a in latin name", "аdmin", "аdmin", "аdmin"},
+		{"latin a in cyrillic name", "Иvан", "Иvан", "иvан"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Normalize(tt.input); got != tt.wantNormal {
+				t.Errorf("Normalize(%q) = %q, want %q", tt.input, got, tt.wantNormal)
+			}
+			canonical := Canonical(tt.input)
+			if canonical != tt.wantCanonical {
+				t.Errorf("Canonical(%q) = %q, want %q", tt.input, canonical, tt.wantCanonical)
+			}
+			// BackfillCanonicalNames stores canonical names once, they must not change when computed again
+			if again := Canonical(canonical); again != canonical {
+				t.Errorf("Canonical(%q) = %q, not stable", canonical, again)
+			}
+		})
+	}
+}
+
+func TestCanonicalConfusables(t *testing.T) {
+	if Canonical("аdmin") == Canonical("admin") {
+		t.Error("Cyrillic and Latin \"a\" have the same canonical form")
+	}
+	if Canonical("ＡＤＭＩＮ") != Canonical("admin") {
+		t.Error("full-width and ascii names have different canonical forms")
+	}
+}
+
+func TestCanonicalEmail(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"User@Example.com", "user@example.com"},
+		{" user@example.com ", "user@example.com"},
+		{"ｕｓｅｒ＠ｅｘａｍｐｌｅ．ｃｏｍ", "user@example.com"},
+	}
+
+	for _, tt := range tests {
+		if got := CanonicalEmail(tt.input); got != tt.want {
+			t.Errorf("CanonicalEmail(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestRulesCheck(t *testing.T) {
+	rules := &Rules{
+		MinLength:      3,
+		MaxLength:      8,
+		AllowedClasses: []string{ClassLetters, ClassDigits},
+		AllowedSymbols: "_.",
+		Reserved:       []string{"admin", "Support"},
+	}
+
+	tests := []struct {
+		name      string
+		username  string
+		wantRules []string
+	}{
+		{"ascii", "alice_1", nil},
+		{"cyrillic", "Иван", nil},
+		{"mixed scripts are letters", "аdmin", nil},
+		{"exactly min", "abc", nil},
+		{"exactly max", "abcdefgh", nil},
+		{"shorter than min", "ab", []string{"username_length"}},
+		{"longer than max", "abcdefghi", []string{"username_length"}},
+		{"length counts characters", "Святослав", []string{"username_length"}},
+		{"length ignores surrounding spaces", "  ab  ", []string{"username_length"}},
+		{"ligatures expand before the length check", "ﬃﬃﬃ", []string{"username_length"}},
+		{"ligature within max", "ﬁnn", nil},
+		{"combining accent counts once", "Jose\u0301", nil},
+		{"full-width counts like ascii", "ＡＢＣＤＥＦＧＨ", nil},
+		{"full-width symbol is allowed", "bob＿1", nil},
+		{"space is not allowed", "bob smit", []string{"username_chars"}},
+		{"symbol not allowed", "bob-1", []string{"username_chars"}},
+		{"reserved", "admin", []string{"username_reserved"}},
+		{"reserved in another case", "SUPPORT", []string{"username_reserved"}},
+		{"reserved in full-width", "ａｄｍｉｎ", []string{"username_reserved"}},
+		{"several violations", "a!", []string{"username_length", "username_chars"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, violation := range rules.Check(tt.username) {
+				got = append(got, violation.Rule)
+			}
+			if !slices.Equal(got, tt.wantRules) {
+				t.Errorf("Check(%q) rules = %v, want %v", tt.username, got, tt.wantRules)
+			}
+		})
+	}
+}
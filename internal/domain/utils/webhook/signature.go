@@ -0,0 +1,53 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+	"webTemplate/internal/domain/common/errorz"
+)
+
+// SignaturePrefix is the scheme prefix of the signature header value.
+const SignaturePrefix = "sha256="
+
+// Sign is a function that returns the signature of a webhook body sent at the given unix timestamp.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return SignaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature is a function that checks the HMAC-SHA256 signature of a webhook body
+/*
+ * secret string - shared webhook secret
+ * timestamp string - unix timestamp the provider signed together with the body
+ * signature string - signature header value in "sha256=<hex>" format
+ * tolerance time.Duration - maximum allowed clock difference, protects from replays
+ */
+func VerifySignature(secret, timestamp string, body []byte, signature string, tolerance time.Duration) error {
+	if secret == "" {
+		return errorz.WebhookNotConfigured
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errorz.InvalidSignature
+	}
+	if diff := time.Since(time.Unix(unix, 0)); diff > tolerance || diff < -tolerance {
+		return errorz.InvalidSignature
+	}
+
+	if !strings.HasPrefix(signature, SignaturePrefix) {
+		return errorz.InvalidSignature
+	}
+	if !hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature)) {
+		return errorz.InvalidSignature
+	}
+
+	return nil
+}
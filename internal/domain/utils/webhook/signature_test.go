@@ -0,0 +1,61 @@
+package webhook
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+	"webTemplate/internal/domain/common/errorz"
+)
+
+func TestVerifySignature(t *testing.T) {
+	const secret = "webhook-secret"
+	const tolerance = 5 * time.Minute
+	body := []byte(`{"events":[]}`)
+
+	// All timestamps are derived from a single instant, so the cases don't depend on when they run
+	start := time.Now()
+	timestampAt := func(offset time.Duration) string {
+		return strconv.FormatInt(start.Add(offset).Unix(), 10)
+	}
+	now := timestampAt(0)
+	valid := Sign(secret, now, body)
+	digest := strings.TrimPrefix(valid, SignaturePrefix)
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp string
+		body      []byte
+		signature string
+		wantErr   error
+	}{
+		{"valid", secret, now, body, valid, nil},
+		{"inside the past tolerance", secret, timestampAt(-tolerance + time.Minute), body, Sign(secret, timestampAt(-tolerance+time.Minute), body), nil},
+		{"inside the future tolerance", secret, timestampAt(tolerance - time.Minute), body, Sign(secret, timestampAt(tolerance-time.Minute), body), nil},
+		{"older than the tolerance", secret, timestampAt(-tolerance - time.Minute), body, Sign(secret, timestampAt(-tolerance-time.Minute), body), errorz.InvalidSignature},
+		{"newer than the tolerance", secret, timestampAt(tolerance + time.Minute), body, Sign(secret, timestampAt(tolerance+time.Minute), body), errorz.InvalidSignature},
+		{"timestamp not a number", secret, "yesterday", body, Sign(secret, "yesterday", body), errorz.InvalidSignature},
+		{"missing prefix", secret, now, body, digest, errorz.InvalidSignature},
+		{"wrong prefix", secret, now, body, "sha1=" + digest, errorz.InvalidSignature},
+		{"uppercase prefix", secret, now, body, "SHA256=" + digest, errorz.InvalidSignature},
+		{"other body", secret, now, []byte(`{"events":[{}]}`), valid, errorz.InvalidSignature},
+		{"other secret", "other-secret", now, body, valid, errorz.InvalidSignature},
+		{"other timestamp", secret, timestampAt(-time.Second), body, valid, errorz.InvalidSignature},
+		{"bad hex", secret, now, body, SignaturePrefix + strings.Repeat("zz", len(digest)/2), errorz.InvalidSignature},
+		{"uppercase hex", secret, now, body, SignaturePrefix + strings.ToUpper(digest), errorz.InvalidSignature},
+		{"truncated", secret, now, body, valid[:len(valid)-2], errorz.InvalidSignature},
+		{"empty signature", secret, now, body, "", errorz.InvalidSignature},
+		{"no secret", "", now, body, valid, errorz.WebhookNotConfigured},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifySignature(tt.secret, tt.timestamp, tt.body, tt.signature, tolerance)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("VerifySignature() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}